@@ -0,0 +1,229 @@
+package nxrm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// AnonymousUserDefault is the user which nexus uses by default for the anonymous requests
+	AnonymousUserDefault = "anonymous"
+
+	ResourceAnonymousAccess = "anonymous-access"
+)
+
+// AnonymousAccess are the settings of the anonymous access. The anonymous requests are made with the permissions of
+// the user UserID of the realm RealmName
+type AnonymousAccess struct {
+	Enabled   bool   `json:"enabled"`
+	UserID    string `json:"userId"`
+	RealmName string `json:"realmName"`
+}
+
+// AnonymousRepository is a repository which is reachable anonymously with the privileges granting the access.
+// Partial is set when every privilege is restricted by a content selector, in which case only the content
+// matching the selectors is reachable
+type AnonymousRepository struct {
+	Name       string   `json:"name"`
+	Format     string   `json:"format"`
+	Privileges []string `json:"privileges"`
+	Partial    bool     `json:"partial"`
+}
+
+// GetAnonymousAccess returns the settings of the anonymous access
+func (c *Client) GetAnonymousAccess(ctx context.Context) (AnonymousAccess, error) {
+	var settings AnonymousAccess
+	if err := c.doSecurity(ctx, "GET", anonymousPath, nil, &settings, nil); err != nil {
+		return AnonymousAccess{}, err
+	}
+	return settings, nil
+}
+
+// SetAnonymousAccess replaces the settings of the anonymous access. The user defaults to AnonymousUserDefault and
+// the realm to RealmLocalAuthorizing, in which case the user must exist in nexus
+func (c *Client) SetAnonymousAccess(ctx context.Context, settings AnonymousAccess) error {
+	if settings.UserID == "" {
+		settings.UserID = AnonymousUserDefault
+	}
+	if settings.RealmName == "" {
+		settings.RealmName = RealmLocalAuthorizing
+	}
+	before, err := c.GetAnonymousAccess(ctx)
+	if err != nil {
+		return err
+	}
+	if settings.Enabled {
+		if err := c.validateAnonymousAccess(ctx, settings); err != nil {
+			return err
+		}
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceAnonymousAccess, Name: settings.UserID, Action: ActionUpdate, Before: before, After: settings})
+	} else if err := c.doSecurity(ctx, "PUT", anonymousPath, settings, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(anonymousAccessUpdatedInfo, settings.Enabled, settings.UserID, settings.RealmName)
+	return nil
+}
+
+// ListAnonymousRepositories prints the repositories which are reachable anonymously
+func (c *Client) ListAnonymousRepositories(ctx context.Context) error {
+	repositories, err := c.GetAnonymousRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	for _, r := range repositories {
+		if r.Partial {
+			fmt.Printf("%s (partially, privileges : %s)\n", r.Name, strings.Join(r.Privileges, ", "))
+		} else {
+			fmt.Println(r.Name)
+		}
+	}
+	fmt.Printf("Number of repositories reachable anonymously : %d\n", len(repositories))
+	return nil
+}
+
+// GetAnonymousRepositories returns the repositories which the anonymous user can read sorted by name.
+// The privileges are resolved from the roles of the anonymous user, including the roles granted by its roles.
+// No repository is returned when the anonymous access is disabled
+func (c *Client) GetAnonymousRepositories(ctx context.Context) ([]AnonymousRepository, error) {
+	settings, err := c.GetAnonymousAccess(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, nil
+	}
+	user, err := c.getUser(ctx, settings.UserID)
+	if err != nil {
+		return nil, err
+	}
+	privileges, err := c.getRolesPrivileges(ctx, user.Roles)
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := c.getRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []AnonymousRepository
+	for _, r := range repositories {
+		repository := AnonymousRepository{Name: r.Name, Format: r.Format, Partial: true}
+		for _, p := range privileges {
+			if !privilegeGrantsRead(p, r) {
+				continue
+			}
+			repository.Privileges = append(repository.Privileges, p.Name)
+			if p.Type != PrivilegeRepositoryContentSelector {
+				repository.Partial = false
+			}
+		}
+		if len(repository.Privileges) > 0 {
+			result = append(result, repository)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// getRolesPrivileges returns the privileges granted by roles and by the roles they contain
+func (c *Client) getRolesPrivileges(ctx context.Context, roleIDs []string) ([]Privilege, error) {
+	roles, err := c.getRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rolesByID := make(map[string]Role)
+	for _, r := range roles {
+		rolesByID[r.RoleID] = r
+	}
+	var privilegeIDs, visited []string
+	pending := append([]string(nil), roleIDs...)
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		if entryExists(visited, id) {
+			continue
+		}
+		visited = append(visited, id)
+		role, ok := rolesByID[id]
+		if !ok {
+			continue
+		}
+		privilegeIDs = append(privilegeIDs, role.Privileges...)
+		pending = append(pending, role.Roles...)
+	}
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Privilege
+	for _, p := range privileges {
+		if entryExists(privilegeIDs, p.ID) || entryExists(privilegeIDs, p.Name) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// validateAnonymousAccess checks that the realm of the anonymous user is available and that the user exists
+// when it is a nexus user
+func (c *Client) validateAnonymousAccess(ctx context.Context, settings AnonymousAccess) error {
+	available, err := c.GetAvailableRealms(ctx)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, r := range available {
+		ids = append(ids, r.ID)
+	}
+	if !entryExists(ids, settings.RealmName) {
+		return newError(ErrInvalidInput, realmNotValidInfo, settings.RealmName, ids)
+	}
+	if settings.RealmName != RealmLocalAuthorizing {
+		return nil
+	}
+	exists, err := c.userExists(ctx, settings.UserID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrUserNotFound, userNotFoundInfo, settings.UserID)
+	}
+	return nil
+}
+
+// readWildcardPatterns are the wildcard privilege patterns which allow reading every repository
+var readWildcardPatterns = []string{"nexus:*", "nexus:repository-view:*", "nexus:repository-view:*:*:*"}
+
+// privilegeGrantsRead reports whether a privilege allows reading the content of a repository.
+// The repository of a privilege is either a repository name, * for every repository or *-<format>
+func privilegeGrantsRead(privilege Privilege, repository Repository) bool {
+	switch privilege.Type {
+	case PrivilegeWildcard:
+		return entryExists(readWildcardPatterns, privilege.Properties.Pattern)
+	case PrivilegeRepositoryView:
+		if format := privilege.Properties.Format; format != "" && format != "*" && format != repository.Format {
+			return false
+		}
+	case PrivilegeRepositoryContentSelector:
+	default:
+		return false
+	}
+	switch repo := privilege.Properties.Repository; {
+	case repo == "*":
+	case strings.HasPrefix(repo, "*-"):
+		if strings.TrimPrefix(repo, "*-") != repository.Format {
+			return false
+		}
+	case repo != repository.Name:
+		return false
+	}
+	for _, action := range strings.Split(privilege.Properties.Actions, ",") {
+		if action = strings.TrimSpace(action); action == "*" || action == "read" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package nxrm
+
+import "testing"
+
+func TestPrivilegeGrantsRead(t *testing.T) {
+	repository := Repository{Name: "maven-releases", Format: "maven2"}
+	tests := []struct {
+		name      string
+		privilege Privilege
+		want      bool
+	}{
+		{"wildcard", Privilege{Type: PrivilegeWildcard, Properties: PrivilegeProperties{Pattern: "nexus:*"}}, true},
+		{"other wildcard", Privilege{Type: PrivilegeWildcard, Properties: PrivilegeProperties{Pattern: "nexus:users:*"}}, false},
+		{"repository view",
+			Privilege{Type: PrivilegeRepositoryView, Properties: PrivilegeProperties{Format: "maven2", Repository: "maven-releases", Actions: "browse,read"}}, true},
+		{"every repository",
+			Privilege{Type: PrivilegeRepositoryView, Properties: PrivilegeProperties{Format: "*", Repository: "*", Actions: "*"}}, true},
+		{"repositories of the format",
+			Privilege{Type: PrivilegeRepositoryView, Properties: PrivilegeProperties{Format: "maven2", Repository: "*-maven2", Actions: "read"}}, true},
+		{"repositories of another format",
+			Privilege{Type: PrivilegeRepositoryView, Properties: PrivilegeProperties{Format: "npm", Repository: "*-npm", Actions: "read"}}, false},
+		{"another repository",
+			Privilege{Type: PrivilegeRepositoryView, Properties: PrivilegeProperties{Format: "maven2", Repository: "maven-snapshots", Actions: "read"}}, false},
+		{"no read action",
+			Privilege{Type: PrivilegeRepositoryView, Properties: PrivilegeProperties{Format: "maven2", Repository: "maven-releases", Actions: "browse, edit"}}, false},
+		{"content selector",
+			Privilege{Type: PrivilegeRepositoryContentSelector, Properties: PrivilegeProperties{Repository: "maven-releases", Actions: "read"}}, true},
+		{"repository admin",
+			Privilege{Type: PrivilegeRepositoryAdmin, Properties: PrivilegeProperties{Format: "maven2", Repository: "maven-releases", Actions: "*"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := privilegeGrantsRead(tt.privilege, repository); got != tt.want {
+				t.Errorf("privilegeGrantsRead() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
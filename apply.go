@@ -0,0 +1,977 @@
+package nxrm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ResourceRepository = "repository"
+	ResourceSelector   = "content-selector"
+	ResourcePrivilege  = "privilege"
+	ResourceRole       = "role"
+
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Spec is the desired state of the repositories, content selectors, privileges and roles of a nexus instance.
+// Fields which are not set in the spec keep their current value in nexus. The fields of the resources read by
+// ParseSpec, LoadSpec and LoadSpecDir are set when they are present in the document, even when they are empty
+// eg: false, 0 or an empty list. The fields of the resources added in go are set when they are not empty
+type Spec struct {
+	Repositories     []Repository      `json:"repositories,omitempty"`
+	ContentSelectors []ContentSelector `json:"contentSelectors,omitempty"`
+	Privileges       []Privilege       `json:"privileges,omitempty"`
+	Roles            []Role            `json:"roles,omitempty"`
+
+	fields specFields
+}
+
+// specFields are the fields present in the documents of the resources of a spec by resource and name
+type specFields map[string]map[string]map[string]interface{}
+
+// ApplyOptions configures how a spec is applied
+type ApplyOptions struct {
+	// Prune deletes the resources which are not in the spec.
+	// Read only privileges and roles which are not created by the library are never deleted
+	Prune bool
+}
+
+// Change is a change of a resource required to reach the desired state.
+// Before is not set for a creation and After is not set for a deletion
+type Change struct {
+	Resource string      `json:"resource"`
+	Name     string      `json:"name"`
+	Action   string      `json:"action"`
+	Before   interface{} `json:"before,omitempty"`
+	After    interface{} `json:"after,omitempty"`
+}
+
+// LoadSpec reads a spec from a yaml or a json file
+func LoadSpec(name string) (Spec, error) {
+	content, err := readFile(name)
+	if err != nil {
+		return Spec{}, err
+	}
+	return ParseSpec([]byte(content))
+}
+
+// ParseSpec parses a yaml or a json spec. Unknown fields are rejected
+func ParseSpec(data []byte) (Spec, error) {
+	var spec Spec
+	if err := decodeSpec(data, &spec); err != nil {
+		return spec, err
+	}
+	// the resources are decoded again as maps to know which fields are present in the document
+	var document struct {
+		Repositories     []map[string]interface{} `json:"repositories"`
+		ContentSelectors []map[string]interface{} `json:"contentSelectors"`
+		Privileges       []map[string]interface{} `json:"privileges"`
+		Roles            []map[string]interface{} `json:"roles"`
+	}
+	if err := decodeSpec(data, &document); err != nil {
+		return spec, err
+	}
+	for i, r := range spec.Repositories {
+		spec.setDocumentFields(ResourceRepository, r.Name, document.Repositories[i])
+	}
+	for i, cs := range spec.ContentSelectors {
+		spec.setDocumentFields(ResourceSelector, cs.Name, document.ContentSelectors[i])
+	}
+	for i, p := range spec.Privileges {
+		spec.setDocumentFields(ResourcePrivilege, p.Name, document.Privileges[i])
+	}
+	for i, r := range spec.Roles {
+		spec.setDocumentFields(ResourceRole, r.RoleID, document.Roles[i])
+	}
+	return spec, nil
+}
+
+// setDocumentFields records the fields present in the document of a resource
+func (s *Spec) setDocumentFields(resource, name string, fields map[string]interface{}) {
+	if s.fields == nil {
+		s.fields = make(specFields)
+	}
+	if s.fields[resource] == nil {
+		s.fields[resource] = make(map[string]map[string]interface{})
+	}
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	s.fields[resource][name] = fields
+}
+
+// decodeDocumentFields records the fields present in data, the document of a resource
+func (s *Spec) decodeDocumentFields(resource, name string, data []byte) error {
+	var fields map[string]interface{}
+	if err := decodeSpec(data, &fields); err != nil {
+		return err
+	}
+	s.setDocumentFields(resource, name, fields)
+	return nil
+}
+
+// documentFields returns the fields present in the document of a resource, or nil when the resource was not read
+// from a document
+func (s Spec) documentFields(resource, name string) map[string]interface{} {
+	return s.fields[resource][name]
+}
+
+// decodeSpec decodes yaml or json data in out using the json field names. Unknown fields are rejected
+func decodeSpec(data []byte, out interface{}) error {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return newError(ErrInvalidInput, specNotValidInfo, err)
+	}
+	if generic == nil {
+		return nil
+	}
+	jsonData, err := json.Marshal(generic)
+	if err != nil {
+		return fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return newError(ErrInvalidInput, specNotValidInfo, err)
+	}
+	return nil
+}
+
+// DiffSpec returns the changes required to reach the desired state of the spec, in the order in which they are applied.
+// Resources are created and updated in dependency order: content selectors, repositories, group repositories,
+// privileges and roles. Resources are deleted in the reverse order
+func (c *Client) DiffSpec(ctx context.Context, spec Spec, opts ApplyOptions) ([]Change, error) {
+	spec, err := normalizeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	live, err := c.loadLiveState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+
+	selectorChanges, err := diffSelectors(spec, live)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, selectorChanges...)
+
+	repositoryChanges, err := c.diffRepositories(ctx, spec, live)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, repositoryChanges...)
+
+	privilegeChanges, err := diffPrivileges(spec, live)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, privilegeChanges...)
+
+	roleChanges, err := diffRoles(spec, live)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, roleChanges...)
+
+	if opts.Prune {
+		changes = append(changes, pruneChanges(spec, live)...)
+	}
+	return changes, nil
+}
+
+// ApplySpec makes the changes required to reach the desired state of the spec and returns the applied changes.
+// ApplySpec stops at the first change which fails
+func (c *Client) ApplySpec(ctx context.Context, spec Spec, opts ApplyOptions) ([]Change, error) {
+	changes, err := c.DiffSpec(ctx, spec, opts)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, change := range changes {
+		if err := c.applyChange(ctx, b, change); err != nil {
+			return changes[:i], fmt.Errorf("%s %s %q : %w", change.Action, change.Resource, change.Name, err)
+		}
+	}
+	return changes, nil
+}
+
+func (c *Client) applyChange(ctx context.Context, b backend, change Change) error {
+	switch after := change.After.(type) {
+	case Repository:
+		if change.Action == ActionCreate {
+			if err := b.createRepository(ctx, after); err != nil {
+				return err
+			}
+			c.logger.Printf(repoCreatedInfo, after.Name)
+			return nil
+		}
+		if err := b.updateRepository(ctx, after); err != nil {
+			return err
+		}
+		c.logger.Printf(repoUpdatedStatus, after.Name)
+	case ContentSelector:
+		if change.Action == ActionCreate {
+			if err := b.createSelector(ctx, after); err != nil {
+				return err
+			}
+			c.logger.Printf(createSelectorSuccessInfo, after.Name)
+			return nil
+		}
+		if err := b.updateSelector(ctx, after); err != nil {
+			return err
+		}
+		c.logger.Printf(updateSelectorSuccessInfo, after.Name)
+	case Privilege:
+		if change.Action == ActionCreate {
+			if err := b.createPrivilege(ctx, after); err != nil {
+				return err
+			}
+			c.logger.Printf(createPrivilegeSuccessInfo, after.Name)
+			return nil
+		}
+		if err := b.updatePrivilege(ctx, after); err != nil {
+			return err
+		}
+		c.logger.Printf(updatePrivilegeSuccessInfo, after.Name)
+	case Role:
+		if change.Action == ActionCreate {
+			if err := b.createRole(ctx, after); err != nil {
+				return err
+			}
+			c.logger.Printf(createRoleSuccessInfo, after.RoleID, after.Roles, after.Privileges)
+			return nil
+		}
+		if err := b.updateRole(ctx, after); err != nil {
+			return err
+		}
+		c.logger.Printf(updateRoleSuccessInfo, after.RoleID)
+	case nil:
+		return c.applyDelete(ctx, b, change)
+	}
+	return nil
+}
+
+func (c *Client) applyDelete(ctx context.Context, b backend, change Change) error {
+	switch before := change.Before.(type) {
+	case Repository:
+		if err := b.deleteRepository(ctx, before.Name); err != nil {
+			return err
+		}
+		c.logger.Printf(repoDeletedInfo, before.Name)
+	case ContentSelector:
+		if err := b.deleteSelector(ctx, before); err != nil {
+			return err
+		}
+		c.logger.Printf(deleteSelectorSuccessInfo, before.Name)
+	case Privilege:
+		if err := b.deletePrivilege(ctx, before); err != nil {
+			return err
+		}
+		c.logger.Printf(deletePrivilegeSuccessInfo, before.Name)
+	case Role:
+		if err := b.deleteRole(ctx, before.RoleID); err != nil {
+			return err
+		}
+		c.logger.Printf(deleteRoleSuccessInfo, before.RoleID)
+	}
+	return nil
+}
+
+// liveState contains the resources available in nexus. Repositories only contain their summary
+type liveState struct {
+	repositories map[string]Repository
+	selectors    map[string]ContentSelector
+	privileges   map[string]Privilege
+	roles        map[string]Role
+}
+
+func (c *Client) loadLiveState(ctx context.Context) (liveState, error) {
+	live := liveState{
+		repositories: make(map[string]Repository),
+		selectors:    make(map[string]ContentSelector),
+		privileges:   make(map[string]Privilege),
+		roles:        make(map[string]Role),
+	}
+	repositories, err := c.getRepositories(ctx)
+	if err != nil {
+		return live, err
+	}
+	for _, r := range repositories {
+		live.repositories[r.Name] = r
+	}
+	selectors, err := c.getSelectors(ctx)
+	if err != nil {
+		return live, err
+	}
+	for _, cs := range selectors {
+		live.selectors[cs.Name] = cs
+	}
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return live, err
+	}
+	for _, p := range privileges {
+		live.privileges[p.Name] = p
+	}
+	roles, err := c.getRoles(ctx)
+	if err != nil {
+		return live, err
+	}
+	for _, r := range roles {
+		live.roles[r.RoleID] = r
+	}
+	return live, nil
+}
+
+// normalizeSpec validates the resources of the spec. The defaults are only set when a resource is created,
+// so that the fields which are not set in the spec keep their current value
+func normalizeSpec(spec Spec) (Spec, error) {
+	var normalized Spec
+	var names []string
+	for _, r := range spec.Repositories {
+		r, err := normalizeRepositorySpec(r)
+		if err != nil {
+			return spec, err
+		}
+		if entryExists(names, r.Name) {
+			return spec, newError(ErrInvalidInput, specDuplicateInfo, ResourceRepository, r.Name)
+		}
+		names = append(names, r.Name)
+		normalized.Repositories = append(normalized.Repositories, r)
+	}
+	names = nil
+	for _, cs := range spec.ContentSelectors {
+		if cs.Name == "" || cs.Attributes.Expression == "" {
+			return spec, newError(ErrInvalidInput, createSelectorRequiredInfo)
+		}
+		if entryExists(names, cs.Name) {
+			return spec, newError(ErrInvalidInput, specDuplicateInfo, ResourceSelector, cs.Name)
+		}
+		names = append(names, cs.Name)
+		normalized.ContentSelectors = append(normalized.ContentSelectors, cs)
+	}
+	names = nil
+	for _, p := range spec.Privileges {
+		if p.Type == "" || p.Type == getPrivilegeType() {
+			if p.Name == "" || p.Properties.ContentSelector == "" || p.Properties.Repository == "" {
+				return spec, newError(ErrInvalidInput, createPrivilegeRequiredInfo)
+			}
+		} else {
+			validated, err := validatePrivilege(p)
+			if err != nil {
+				return spec, err
+			}
+			p = validated
+		}
+		if entryExists(names, p.Name) {
+			return spec, newError(ErrInvalidInput, specDuplicateInfo, ResourcePrivilege, p.Name)
+		}
+		names = append(names, p.Name)
+		if p.ID == "" {
+			p.ID = p.Name
+		}
+		if p.Properties.Actions == "read" || p.Properties.Actions == "write" {
+			p.Properties.Actions = getPrivilegeActions(p.Properties.Actions)
+		}
+		normalized.Privileges = append(normalized.Privileges, p)
+	}
+	names = nil
+	for _, r := range spec.Roles {
+		if r.RoleID == "" {
+			return spec, newError(ErrInvalidInput, roleIDRequiredInfo)
+		}
+		if entryExists(names, r.RoleID) {
+			return spec, newError(ErrInvalidInput, specDuplicateInfo, ResourceRole, r.RoleID)
+		}
+		names = append(names, r.RoleID)
+		normalized.Roles = append(normalized.Roles, r)
+	}
+	normalized.fields = spec.fields
+	return normalized, nil
+}
+
+// normalizeRepositorySpec validates a repository of a spec and sets its format, type and recipe
+func normalizeRepositorySpec(r Repository) (Repository, error) {
+	if r.Name == "" {
+		return r, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	repoType := repositoryType(r)
+	if !entryExists(RepoType, repoType) {
+		return r, newError(ErrInvalidInput, specRepoTypeNotValidInfo, r.Name, RepoType)
+	}
+	if r.Format == "" && r.Recipe != "" {
+		r.Format = r.Recipe[:len(r.Recipe)-len(repoType)-1]
+	}
+	if r.Format == "maven2" {
+		r.Format = "maven"
+	}
+	format, err := validateRepositoryFormat(r.Format)
+	if err != nil {
+		return r, err
+	}
+	r.Format, r.Type, r.Recipe = format, repoType, fmt.Sprintf("%s-%s", format, repoType)
+	if err := validateFormatType(format, repoType); err != nil {
+		return r, err
+	}
+
+	if remoteURL := r.Attributes.Proxy.RemoteURL; remoteURL != "" {
+		if err := validateRemoteURL(remoteURL); err != nil {
+			return r, err
+		}
+	}
+	// a username without a password keeps the password stored in nexus, which is not exported by default
+	if auth := r.Attributes.Httpclient.Authentication; auth.Username == "" && auth.Password != "" {
+		return r, newError(ErrInvalidInput, proxyCredsNotValidInfo)
+	}
+	return r, nil
+}
+
+// newRepositorySpec sets the defaults of a repository which is created like CreateHosted, CreateProxy
+// and CreateGroup do. Maven repositories are release repositories unless their version policy is set, the write
+// policy defaults to the one of the version policy
+func newRepositorySpec(r Repository) (Repository, error) {
+	attributes := &r.Attributes
+	attributes.Storage.BlobStoreName = getBlobStoreName(attributes.Storage.BlobStoreName)
+	versionPolicy := attributes.Maven.VersionPolicy
+	releases := r.Format == "maven2" && (versionPolicy == "" || versionPolicy == getVersionPolicy(true))
+	if attributes.Storage.WritePolicy == "" {
+		attributes.Storage.WritePolicy = getWritePolicy(releases)
+	}
+	switch r.Type {
+	case "proxy":
+		if attributes.Proxy.RemoteURL == "" {
+			return r, newError(ErrInvalidInput, proxyRepoRequiredInfo)
+		}
+		auth := attributes.Httpclient.Authentication
+		if err := validateProxyAuthInfo(auth.Username, auth.Password); err != nil {
+			return r, err
+		}
+	case "group":
+		if len(attributes.Group.MemberNames) == 0 {
+			return r, newError(ErrInvalidInput, groupRepoRequiredInfo)
+		}
+	}
+	switch r.Format {
+	case "maven2":
+		if attributes.Maven.VersionPolicy == "" {
+			attributes.Maven.VersionPolicy = getVersionPolicy(releases)
+		}
+		if attributes.Maven.LayoutPolicy == "" {
+			attributes.Maven.LayoutPolicy = "STRICT"
+		}
+	case "docker":
+		if attributes.Docker.HTTPPort == 0 && attributes.Docker.HTTPSPort == 0 {
+			return r, newError(ErrInvalidInput, dockerPortsInfo)
+		}
+	}
+	return r, validateFormatAttributes(r)
+}
+
+// newSelectorSpec sets the defaults of a content selector which is created
+func newSelectorSpec(cs ContentSelector) ContentSelector {
+	if cs.Type == "" {
+		cs.Type = contentSelectorType
+	}
+	cs.Description = getSelectorDescription(cs.Description)
+	return cs
+}
+
+// newPrivilegeSpec sets the defaults of a privilege which is created
+func newPrivilegeSpec(p Privilege) Privilege {
+	if p.Type == "" {
+		p.Type = getPrivilegeType()
+	}
+	if p.Properties.Actions == "" {
+		p.Properties.Actions = getPrivilegeActions(p.Properties.Actions)
+	}
+	p.Description = getPrivilegeDescription(p.Description)
+	return p
+}
+
+// newRoleSpec sets the defaults of a role which is created
+func newRoleSpec(r Role) Role {
+	if r.Name == "" {
+		r.Name = r.RoleID
+	}
+	if r.Source == "" {
+		r.Source = getRoleSource()
+	}
+	r.Description = getRoleDesc(r.Description)
+	return r
+}
+
+func diffSelectors(spec Spec, live liveState) ([]Change, error) {
+	var changes []Change
+	for _, cs := range spec.ContentSelectors {
+		current, ok := live.selectors[cs.Name]
+		if !ok {
+			changes = append(changes, Change{Resource: ResourceSelector, Name: cs.Name, Action: ActionCreate, After: newSelectorSpec(cs)})
+			continue
+		}
+		var updated ContentSelector
+		changed, err := reconcile(current, cs, spec.documentFields(ResourceSelector, cs.Name), &updated)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changes = append(changes, Change{Resource: ResourceSelector, Name: cs.Name, Action: ActionUpdate, Before: current, After: updated})
+		}
+	}
+	return changes, nil
+}
+
+// diffRepositories returns the changes of the repositories. Group repositories are changed after their members
+func (c *Client) diffRepositories(ctx context.Context, spec Spec, live liveState) ([]Change, error) {
+	desired := make(map[string]Repository)
+	var names []string
+	for _, r := range spec.Repositories {
+		desired[r.Name] = r
+		if r.Type != "group" {
+			names = append(names, r.Name)
+		}
+	}
+	for _, r := range spec.Repositories {
+		if r.Type == "group" {
+			names = append(names, r.Name)
+		}
+	}
+	ordered, err := dependencyOrder(names, func(name string) []string {
+		return desired[name].Attributes.Group.MemberNames
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the repositories of the spec are validated before any change is made, the repositories which are created
+	// are members of the groups as they are created first
+	pending := make(map[string]Repository)
+	var changes []Change
+	for _, name := range ordered {
+		r := desired[name]
+		if _, ok := live.repositories[name]; !ok {
+			r, err := newRepositorySpec(r)
+			if err != nil {
+				return nil, err
+			}
+			if err := c.validateReferences(ctx, r); err != nil {
+				return nil, err
+			}
+			if r.Type == "group" {
+				if err := c.validateGroupMemberNames(ctx, r, pending); err != nil {
+					return nil, err
+				}
+			}
+			pending[name] = r
+			changes = append(changes, Change{Resource: ResourceRepository, Name: name, Action: ActionCreate, After: r})
+			continue
+		}
+		current, err := c.getRepository(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if current.Recipe != r.Recipe {
+			return nil, newError(ErrInvalidInput, specRecipeChangedInfo, name, current.Recipe, r.Recipe)
+		}
+		updated := current
+		attributeFields := documentObject(spec.documentFields(ResourceRepository, name), "attributes")
+		changed, err := reconcile(current.Attributes, r.Attributes, attributeFields, &updated.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if r.Online != nil && (current.Online == nil || *r.Online != *current.Online) {
+			updated.Online = r.Online
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if err := c.validateAttributes(ctx, current, updated, pending); err != nil {
+			return nil, err
+		}
+		changes = append(changes, Change{Resource: ResourceRepository, Name: name, Action: ActionUpdate, Before: current, After: updated})
+	}
+	return changes, nil
+}
+
+func diffPrivileges(spec Spec, live liveState) ([]Change, error) {
+	selectors := make(map[string]bool)
+	for _, cs := range spec.ContentSelectors {
+		selectors[cs.Name] = true
+	}
+	repositories := make(map[string]bool)
+	for _, r := range spec.Repositories {
+		repositories[r.Name] = true
+	}
+	var changes []Change
+	for _, p := range spec.Privileges {
+		switch p.Type {
+		case "", getPrivilegeType():
+			if _, ok := live.selectors[p.Properties.ContentSelector]; !ok && !selectors[p.Properties.ContentSelector] {
+				return nil, newError(ErrSelectorNotFound, selectorNotFoundInfo, p.Properties.ContentSelector)
+			}
+			fallthrough
+		case PrivilegeRepositoryView, PrivilegeRepositoryAdmin:
+			repoName := p.Properties.Repository
+			if _, ok := live.repositories[repoName]; !ok && !repositories[repoName] && !isRepositoryWildcard(repoName) {
+				return nil, newError(ErrRepositoryNotFound, repositoryNotFoundInfo, repoName)
+			}
+		}
+		current, ok := live.privileges[p.Name]
+		if !ok {
+			changes = append(changes, Change{Resource: ResourcePrivilege, Name: p.Name, Action: ActionCreate, After: newPrivilegeSpec(p)})
+			continue
+		}
+		p.ID = current.ID
+		var updated Privilege
+		changed, err := reconcile(current, p, spec.documentFields(ResourcePrivilege, p.Name), &updated)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changes = append(changes, Change{Resource: ResourcePrivilege, Name: p.Name, Action: ActionUpdate, Before: current, After: updated})
+		}
+	}
+	return changes, nil
+}
+
+// diffRoles returns the changes of the roles. The privileges of the roles in the spec are names which are
+// converted to the ids used by nexus. Roles are changed after the roles they contain
+func diffRoles(spec Spec, live liveState) ([]Change, error) {
+	privilegeIDs := make(map[string]string)
+	for name, p := range live.privileges {
+		privilegeIDs[name] = p.ID
+	}
+	for _, p := range spec.Privileges {
+		if current, ok := live.privileges[p.Name]; ok {
+			p.ID = current.ID
+		}
+		privilegeIDs[p.Name] = p.ID
+	}
+	desired := make(map[string]Role)
+	var ids []string
+	for _, r := range spec.Roles {
+		var privileges []string
+		for _, name := range r.Privileges {
+			id, ok := privilegeIDs[name]
+			if !ok {
+				return nil, newError(ErrPrivilegeNotFound, rolePrivilegeNotFoundInfo, name)
+			}
+			privileges = append(privileges, id)
+		}
+		r.Privileges = privileges
+		desired[r.RoleID] = r
+		ids = append(ids, r.RoleID)
+	}
+	for _, r := range spec.Roles {
+		for _, member := range r.Roles {
+			if member == r.RoleID {
+				return nil, newError(ErrInvalidInput, cannotBeSameRoleInfo, member, r.RoleID)
+			}
+			if _, ok := live.roles[member]; !ok && !entryExists(ids, member) {
+				return nil, newError(ErrRoleNotFound, roleMemberNotFoundInfo, member)
+			}
+		}
+	}
+	ordered, err := dependencyOrder(ids, func(id string) []string {
+		return desired[id].Roles
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, id := range ordered {
+		r := desired[id]
+		current, ok := live.roles[id]
+		if !ok {
+			changes = append(changes, Change{Resource: ResourceRole, Name: id, Action: ActionCreate, After: newRoleSpec(r)})
+			continue
+		}
+		// the order of the roles and the privileges of a role is not significant
+		r.Roles = append([]string(nil), r.Roles...)
+		sort.Strings(r.Roles)
+		sort.Strings(r.Privileges)
+		current.Roles = append([]string(nil), current.Roles...)
+		current.Privileges = append([]string(nil), current.Privileges...)
+		sort.Strings(current.Roles)
+		sort.Strings(current.Privileges)
+		var updated Role
+		changed, err := reconcile(current, r, spec.documentFields(ResourceRole, id), &updated)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changes = append(changes, Change{Resource: ResourceRole, Name: id, Action: ActionUpdate, Before: current, After: updated})
+		}
+	}
+	return changes, nil
+}
+
+// pruneChanges returns the deletions of the resources which are not in the spec, in reverse dependency order
+func pruneChanges(spec Spec, live liveState) []Change {
+	var changes []Change
+	var ids []string
+	for _, r := range spec.Roles {
+		ids = append(ids, r.RoleID)
+	}
+	for _, id := range sortedKeys(live.roles) {
+		r := live.roles[id]
+		if !entryExists(ids, id) && !r.ReadOnly && r.Source == getRoleSource() {
+			changes = append(changes, Change{Resource: ResourceRole, Name: id, Action: ActionDelete, Before: r})
+		}
+	}
+	var names []string
+	for _, p := range spec.Privileges {
+		names = append(names, p.Name)
+	}
+	for _, name := range sortedKeys(live.privileges) {
+		p := live.privileges[name]
+		if !entryExists(names, name) && !p.ReadOnly {
+			changes = append(changes, Change{Resource: ResourcePrivilege, Name: name, Action: ActionDelete, Before: p})
+		}
+	}
+	names = nil
+	for _, r := range spec.Repositories {
+		names = append(names, r.Name)
+	}
+	var groups, repositories []Change
+	for _, name := range sortedKeys(live.repositories) {
+		r := live.repositories[name]
+		if entryExists(names, name) {
+			continue
+		}
+		change := Change{Resource: ResourceRepository, Name: name, Action: ActionDelete, Before: r}
+		if r.Type == "group" {
+			groups = append(groups, change)
+		} else {
+			repositories = append(repositories, change)
+		}
+	}
+	changes = append(changes, groups...)
+	changes = append(changes, repositories...)
+	names = nil
+	for _, cs := range spec.ContentSelectors {
+		names = append(names, cs.Name)
+	}
+	for _, name := range sortedKeys(live.selectors) {
+		if !entryExists(names, name) {
+			changes = append(changes, Change{Resource: ResourceSelector, Name: name, Action: ActionDelete, Before: live.selectors[name]})
+		}
+	}
+	return changes
+}
+
+// dependencyOrder orders names so that the dependencies of a name which are part of names come before the name.
+// The order of names is kept otherwise
+func dependencyOrder(names []string, dependencies func(name string) []string) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int)
+	var ordered []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return newError(ErrInvalidInput, specDependencyCycleInfo, name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dependency := range dependencies(name) {
+			if entryExists(names, dependency) {
+				if err := visit(dependency); err != nil {
+					return err
+				}
+			}
+		}
+		state[name] = visited
+		ordered = append(ordered, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// reconcile sets the fields of desired which are set in live and stores the result in out. The fields which are set
+// are the fields present in document, the document desired was read from, or the fields which are not empty when
+// document is nil. It reports whether a field of desired differs from live
+func reconcile(live, desired interface{}, document map[string]interface{}, out interface{}) (bool, error) {
+	var liveFields, desiredFields map[string]interface{}
+	if err := convert(live, &liveFields); err != nil {
+		return false, err
+	}
+	if err := convert(desired, &desiredFields); err != nil {
+		return false, err
+	}
+	if document != nil {
+		desiredFields = presentFields(desiredFields, document)
+	} else {
+		desiredFields = setFields(desiredFields)
+	}
+	if !fieldsChanged(desiredFields, liveFields) {
+		return false, nil
+	}
+	mergeFields(liveFields, desiredFields)
+	return true, convert(liveFields, out)
+}
+
+// setFields removes the fields which are not set, including false booleans
+func setFields(fields map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case bool:
+			if !v {
+				continue
+			}
+		case string:
+			if v == "" {
+				continue
+			}
+		case float64:
+			if v == 0 {
+				continue
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		case map[string]interface{}:
+			v = setFields(v)
+			if len(v) == 0 {
+				continue
+			}
+			value = v
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// presentFields keeps the fields of values which are present in document, the document values were decoded from.
+// The fields which are present in document but not in values, as they are omitted when empty, take the value of
+// document
+func presentFields(values, document map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for key, documentValue := range document {
+		key, value, ok := lookupField(values, key)
+		if !ok {
+			result[key] = documentValue
+			continue
+		}
+		if d, ok := documentValue.(map[string]interface{}); ok {
+			if v, ok := value.(map[string]interface{}); ok {
+				value = presentFields(v, d)
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// documentObject returns the fields of an object of a document, the object has no field when it is not present.
+// nil is returned when document is nil
+func documentObject(document map[string]interface{}, key string) map[string]interface{} {
+	if document == nil {
+		return nil
+	}
+	if _, value, ok := lookupField(document, key); ok {
+		if object, ok := value.(map[string]interface{}); ok {
+			return object
+		}
+	}
+	return make(map[string]interface{})
+}
+
+// lookupField returns the name and the value of a field. The names are compared case insensitively like
+// encoding/json does when a field has no exact match
+func lookupField(fields map[string]interface{}, key string) (string, interface{}, bool) {
+	if value, ok := fields[key]; ok {
+		return key, value, true
+	}
+	for name, value := range fields {
+		if strings.EqualFold(name, key) {
+			return name, value, true
+		}
+	}
+	return key, nil, false
+}
+
+// fieldsChanged checks if a field of desired differs from live.
+// Passwords are not returned by nexus, hence they are not compared. Empty lists are equal to missing lists
+func fieldsChanged(desired, live map[string]interface{}) bool {
+	for key, value := range desired {
+		if key == "password" {
+			continue
+		}
+		if isEmptyList(value) && isEmptyList(live[key]) {
+			continue
+		}
+		if d, ok := value.(map[string]interface{}); ok {
+			l, _ := live[key].(map[string]interface{})
+			if fieldsChanged(d, l) {
+				return true
+			}
+			continue
+		}
+		if !reflect.DeepEqual(value, live[key]) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyList reports whether a field is an empty or a missing list
+func isEmptyList(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	list, ok := value.([]interface{})
+	return ok && len(list) == 0
+}
+
+// mergeFields sets the fields of desired in live
+func mergeFields(live, desired map[string]interface{}) {
+	for key, value := range desired {
+		if d, ok := value.(map[string]interface{}); ok {
+			if l, ok := live[key].(map[string]interface{}); ok {
+				mergeFields(l, d)
+				continue
+			}
+		}
+		live[key] = value
+	}
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
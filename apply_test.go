@@ -0,0 +1,253 @@
+package nxrm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSetFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		want   map[string]interface{}
+	}{
+		{"empty values are removed",
+			map[string]interface{}{"a": nil, "b": false, "c": "", "d": float64(0), "e": []interface{}{}, "f": map[string]interface{}{}},
+			map[string]interface{}{}},
+		{"set values are kept",
+			map[string]interface{}{"a": true, "b": "x", "c": float64(1), "d": []interface{}{"x"}},
+			map[string]interface{}{"a": true, "b": "x", "c": float64(1), "d": []interface{}{"x"}}},
+		{"objects are cleaned",
+			map[string]interface{}{"a": map[string]interface{}{"b": "", "c": "x"}, "d": map[string]interface{}{"e": false}},
+			map[string]interface{}{"a": map[string]interface{}{"c": "x"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setFields(tt.fields); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	live := Role{RoleID: "role", Name: "name", Description: "description", Privileges: []string{"a", "b"}}
+	tests := []struct {
+		name     string
+		desired  Role
+		document map[string]interface{}
+		changed  bool
+		want     Role
+	}{
+		{"same fields", Role{RoleID: "role", Name: "name"}, nil, false, live},
+		{"changed field", Role{RoleID: "role", Description: "changed"}, nil, true,
+			Role{RoleID: "role", Name: "name", Description: "changed", Privileges: []string{"a", "b"}}},
+		{"empty fields keep their value without a document", Role{RoleID: "role"}, nil, false, live},
+		{"empty fields of a document are set", Role{RoleID: "role"},
+			map[string]interface{}{"roleId": "role", "description": "", "privileges": []interface{}{}}, true,
+			Role{RoleID: "role", Name: "name"}},
+		{"fields missing from a document keep their value", Role{RoleID: "role", Description: "changed"},
+			map[string]interface{}{"roleId": "role"}, false, live},
+		{"empty lists equal missing lists", Role{RoleID: "role"},
+			map[string]interface{}{"roles": []interface{}{}}, false, live},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := live
+			changed, err := reconcile(live, tt.desired, tt.document, &got)
+			if err != nil {
+				t.Fatalf("reconcile() error = %v", err)
+			}
+			if changed != tt.changed {
+				t.Errorf("reconcile() changed = %v, want %v", changed, tt.changed)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reconcile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileIgnoresPasswords(t *testing.T) {
+	live := Attributes{Httpclient: HttpClient{Authentication: HttpClientAuth{Username: "user"}}}
+	desired := Attributes{Httpclient: HttpClient{Authentication: HttpClientAuth{Username: "user", Password: "secret"}}}
+	var got Attributes
+	changed, err := reconcile(live, desired, nil, &got)
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	if changed {
+		t.Errorf("reconcile() changed = true, want false as nexus does not return the password")
+	}
+}
+
+func TestParseSpecZeroValues(t *testing.T) {
+	spec, err := ParseSpec([]byte(`
+repositories:
+  - name: maven-releases
+    format: maven
+    type: hosted
+    online: false
+    attributes:
+      storage:
+        strictContentTypeValidation: false
+      cleanup:
+        policyName: ""
+      negativeCache:
+        timeToLive: 0
+`))
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+	live := Attributes{
+		Storage:       Storage{BlobStoreName: "default", StrictContentTypeValidation: true, WritePolicy: "ALLOW_ONCE"},
+		Cleanup:       Cleanup{PolicyNames: []string{"daily"}},
+		NegativeCache: NegetiveCache{Enabled: true, TimeToLive: 1440},
+	}
+	document := documentObject(spec.documentFields(ResourceRepository, "maven-releases"), "attributes")
+	var got Attributes
+	changed, err := reconcile(live, spec.Repositories[0].Attributes, document, &got)
+	if err != nil {
+		t.Fatalf("reconcile() error = %v", err)
+	}
+	want := Attributes{
+		Storage:       Storage{BlobStoreName: "default", WritePolicy: "ALLOW_ONCE"},
+		NegativeCache: NegetiveCache{Enabled: true},
+	}
+	if !changed || !reflect.DeepEqual(got, want) {
+		t.Errorf("reconcile() = %v, %+v, want true, %+v", changed, got, want)
+	}
+	if online := spec.Repositories[0].Online; online == nil || *online {
+		t.Errorf("Online = %v, want false", online)
+	}
+}
+
+func TestDependencyOrder(t *testing.T) {
+	dependencies := map[string][]string{
+		"group":  {"proxy", "hosted"},
+		"parent": {"group", "missing"},
+		"cycle1": {"cycle2"},
+		"cycle2": {"cycle1"},
+	}
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{"no dependencies", []string{"b", "a"}, []string{"b", "a"}, false},
+		{"dependencies first", []string{"group", "hosted", "proxy"}, []string{"proxy", "hosted", "group"}, false},
+		{"nested dependencies", []string{"parent", "group", "proxy"}, []string{"proxy", "group", "parent"}, false},
+		{"cycle", []string{"cycle1", "cycle2"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := dependencyOrder(tt.names, func(name string) []string { return dependencies[name] })
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("dependencyOrder() error = %v, want %v", err, ErrInvalidInput)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("dependencyOrder() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dependencyOrder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPruneChanges(t *testing.T) {
+	live := liveState{
+		repositories: map[string]Repository{
+			"kept":   {Name: "kept", Type: "hosted"},
+			"hosted": {Name: "hosted", Type: "hosted"},
+			"group":  {Name: "group", Type: "group"},
+		},
+		selectors: map[string]ContentSelector{"selector": {Name: "selector"}},
+		privileges: map[string]Privilege{
+			"privilege": {Name: "privilege"},
+			"builtin":   {Name: "builtin", ReadOnly: true},
+		},
+		roles: map[string]Role{
+			"role":     {RoleID: "role", Source: getRoleSource()},
+			"readonly": {RoleID: "readonly", Source: getRoleSource(), ReadOnly: true},
+			"external": {RoleID: "external", Source: "LDAP"},
+		},
+	}
+	spec := Spec{Repositories: []Repository{{Name: "kept"}}}
+	var got []string
+	for _, change := range pruneChanges(spec, live) {
+		if change.Action != ActionDelete {
+			t.Errorf("pruneChanges() action = %q, want %q", change.Action, ActionDelete)
+		}
+		got = append(got, change.Resource+"/"+change.Name)
+	}
+	want := []string{"role/role", "privilege/privilege", "repository/group", "repository/hosted", "content-selector/selector"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pruneChanges() = %v, want %v", got, want)
+	}
+}
+
+func TestNewRepositorySpecPolicies(t *testing.T) {
+	tests := []struct {
+		name          string
+		repository    Repository
+		writePolicy   string
+		versionPolicy string
+	}{
+		{"maven defaults to releases", Repository{Format: "maven2", Type: "hosted"}, "ALLOW_ONCE", "RELEASE"},
+		{"maven snapshots", Repository{Format: "maven2", Type: "hosted", Attributes: Attributes{Maven: Maven{VersionPolicy: "SNAPSHOT"}}}, "ALLOW", "SNAPSHOT"},
+		{"write policy is kept", Repository{Format: "maven2", Type: "hosted", Attributes: Attributes{Storage: Storage{WritePolicy: "DENY"}}}, "DENY", "RELEASE"},
+		{"other formats", Repository{Format: "raw", Type: "hosted"}, "ALLOW", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.repository.Name = "repository"
+			got, err := newRepositorySpec(tt.repository)
+			if err != nil {
+				t.Fatalf("newRepositorySpec() error = %v", err)
+			}
+			if got.Attributes.Storage.WritePolicy != tt.writePolicy || got.Attributes.Maven.VersionPolicy != tt.versionPolicy {
+				t.Errorf("newRepositorySpec() policies = %q, %q, want %q, %q", got.Attributes.Storage.WritePolicy,
+					got.Attributes.Maven.VersionPolicy, tt.writePolicy, tt.versionPolicy)
+			}
+		})
+	}
+}
+
+func TestProxyCredentialsSpec(t *testing.T) {
+	proxy := func(username, password string) Repository {
+		return Repository{Name: "proxy", Format: "raw", Type: "proxy", Attributes: Attributes{
+			Proxy:      Proxy{RemoteURL: "https://example.com"},
+			Httpclient: HttpClient{Authentication: HttpClientAuth{Username: username, Password: password}},
+		}}
+	}
+	tests := []struct {
+		name         string
+		repository   Repository
+		normalizeErr bool
+		newErr       bool
+	}{
+		{"credentials", proxy("user", "secret"), false, false},
+		{"exported credentials keep the stored password", proxy("user", ""), false, true},
+		{"password without username", proxy("", "secret"), true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := normalizeRepositorySpec(tt.repository)
+			if (err != nil) != tt.normalizeErr {
+				t.Fatalf("normalizeRepositorySpec() error = %v, want error %v", err, tt.normalizeErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := newRepositorySpec(r); (err != nil) != tt.newErr {
+				t.Errorf("newRepositorySpec() error = %v, want error %v", err, tt.newErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package nxrm
+
+import (
+	"context"
+	"sync"
+)
+
+// Backend selects the API used to manage repositories, content selectors, privileges and roles
+type Backend string
+
+const (
+	// BackendScript uses the groovy scripts executed through the script API
+	BackendScript Backend = "script"
+	// BackendREST uses the REST endpoints of nexus under /service/rest/v1
+	BackendREST Backend = "rest"
+	// BackendAuto uses the REST endpoints when they are available and falls back to the script API
+	BackendAuto Backend = "auto"
+)
+
+// backend implements the operations which differ between the script API and the REST API
+type backend interface {
+	name() Backend
+
+	getRepository(ctx context.Context, name string) (Repository, error)
+	createRepository(ctx context.Context, repository Repository) error
+	updateGroupMembers(ctx context.Context, repository Repository) error
+	updateRepository(ctx context.Context, repository Repository) error
+	deleteRepository(ctx context.Context, name string) error
+
+	getSelectors(ctx context.Context) ([]ContentSelector, error)
+	createSelector(ctx context.Context, selector ContentSelector) error
+	updateSelector(ctx context.Context, selector ContentSelector) error
+	deleteSelector(ctx context.Context, selector ContentSelector) error
+
+	getPrivileges(ctx context.Context) ([]Privilege, error)
+	createPrivilege(ctx context.Context, privilege Privilege) error
+	updatePrivilege(ctx context.Context, privilege Privilege) error
+	deletePrivilege(ctx context.Context, privilege Privilege) error
+
+	getRoles(ctx context.Context) ([]Role, error)
+	createRole(ctx context.Context, role Role) error
+	updateRole(ctx context.Context, role Role) error
+	deleteRole(ctx context.Context, id string) error
+}
+
+// backendResolver resolves the backend of a client once
+type backendResolver struct {
+	mu       sync.Mutex
+	resolved backend
+}
+
+// backend returns the backend selected for the client, which only records the changes in dry run mode
+func (c *Client) backend(ctx context.Context) (backend, error) {
+	b, err := c.resolveBackend(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.plan != nil {
+		return &planBackend{backend: b, plan: c.plan}, nil
+	}
+	return b, nil
+}
+
+// resolveBackend returns the backend selected for the client.
+// With BackendAuto the REST API is probed on the first call and the result is reused afterwards
+func (c *Client) resolveBackend(ctx context.Context) (backend, error) {
+	c.resolver.mu.Lock()
+	defer c.resolver.mu.Unlock()
+	if c.resolver.resolved != nil {
+		return c.resolver.resolved, nil
+	}
+	switch c.backendType {
+	case BackendScript, "":
+		c.resolver.resolved = &scriptBackend{c: c}
+	case BackendREST:
+		c.resolver.resolved = &restBackend{c: c}
+	case BackendAuto:
+		caps, err := c.Capabilities(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case caps.Has(FeatureRESTSecurity) && caps.Has(FeatureRESTRepositories):
+			c.resolver.resolved = &restBackend{c: c}
+		case caps.Has(FeatureScriptAPI):
+			c.resolver.resolved = &scriptBackend{c: c}
+		default:
+			return nil, newError(ErrNotSupported, noBackendAvailableInfo, caps.Version, caps.Edition)
+		}
+		if c.debug {
+			c.logger.Printf(backendDetectedInfo, c.resolver.resolved.name())
+		}
+	default:
+		return nil, newError(ErrInvalidInput, backendNotValidInfo, c.backendType, []Backend{BackendScript, BackendREST, BackendAuto})
+	}
+	return c.resolver.resolved, nil
+}
@@ -0,0 +1,352 @@
+package nxrm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	BlobStoreFile = "File"
+	BlobStoreS3   = "S3"
+
+	ResourceBlobStore = "blob-store"
+)
+
+// BlobStore is a blob store in which repositories store their content.
+// Path is only set for file blob stores and BucketConfiguration for S3 blob stores
+type BlobStore struct {
+	Name                  string                 `json:"name"`
+	Type                  string                 `json:"type"`
+	BlobCount             int64                  `json:"blobCount"`
+	TotalSizeInBytes      int64                  `json:"totalSizeInBytes"`
+	AvailableSpaceInBytes int64                  `json:"availableSpaceInBytes"`
+	SoftQuota             *SoftQuota             `json:"softQuota,omitempty"`
+	Path                  string                 `json:"path,omitempty"`
+	BucketConfiguration   *S3BucketConfiguration `json:"bucketConfiguration,omitempty"`
+}
+
+// SoftQuota raises an alert in nexus when a blob store exceeds the limit in bytes.
+// The type is one of QuotaTypes
+type SoftQuota struct {
+	Type  string `json:"type"`
+	Limit int64  `json:"limit"`
+}
+
+// S3BucketConfiguration is the bucket of a S3 blob store and the credentials used to access it
+type S3BucketConfiguration struct {
+	Bucket                   S3Bucket                    `json:"bucket"`
+	BucketSecurity           *S3BucketSecurity           `json:"bucketSecurity,omitempty"`
+	AdvancedBucketConnection *S3AdvancedBucketConnection `json:"advancedBucketConnection,omitempty"`
+}
+
+// S3Bucket is a S3 bucket. Expiration is the number of days after which deleted blobs are removed from the bucket
+type S3Bucket struct {
+	Region     string `json:"region"`
+	Name       string `json:"name"`
+	Prefix     string `json:"prefix,omitempty"`
+	Expiration int    `json:"expiration"`
+}
+
+// S3BucketSecurity contains the credentials of a S3 bucket. The IAM role of nexus is used when they are not set
+type S3BucketSecurity struct {
+	AccessKeyID     string `json:"accessKeyId,omitempty"`
+	SecretAccessKey string `json:"secretAccessKey,omitempty"`
+	Role            string `json:"role,omitempty"`
+	SessionToken    string `json:"sessionToken,omitempty"`
+}
+
+// S3AdvancedBucketConnection is used for S3 compatible services eg: minio
+type S3AdvancedBucketConnection struct {
+	Endpoint       string `json:"endpoint,omitempty"`
+	SignerType     string `json:"signerType,omitempty"`
+	ForcePathStyle bool   `json:"forcePathStyle"`
+}
+
+// BlobStoreUsage lists the repositories which store their content in a blob store
+type BlobStoreUsage struct {
+	BlobStore    string   `json:"blobStore"`
+	Repositories []string `json:"repositories"`
+}
+
+// restFileBlobStore is the request body to create or update a file blob store
+type restFileBlobStore struct {
+	Name      string     `json:"name,omitempty"`
+	Path      string     `json:"path"`
+	SoftQuota *SoftQuota `json:"softQuota,omitempty"`
+}
+
+// restS3BlobStore is the request body to create or update a S3 blob store
+type restS3BlobStore struct {
+	Name                string                 `json:"name"`
+	SoftQuota           *SoftQuota             `json:"softQuota,omitempty"`
+	BucketConfiguration *S3BucketConfiguration `json:"bucketConfiguration"`
+}
+
+// ListBlobStores prints the details of a blob store when a name is provided,
+// otherwise prints the names of all the blob stores
+func (c *Client) ListBlobStores(ctx context.Context, name string) error {
+	if name != "" {
+		blobStore, err := c.GetBlobStore(ctx, name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Name: %s\nType: %s\nBlobs: %d\nSize: %d bytes\n", blobStore.Name, blobStore.Type, blobStore.BlobCount, blobStore.TotalSizeInBytes)
+		return nil
+	}
+	blobStores, err := c.GetBlobStores(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, bs := range blobStores {
+		fmt.Println(bs.Name)
+	}
+	fmt.Printf("Number of blob stores : %d\n", len(blobStores))
+	return nil
+}
+
+// GetBlobStores returns the blob stores matching the options with their usage, without the details per type
+func (c *Client) GetBlobStores(ctx context.Context, opts ListOptions) ([]BlobStore, error) {
+	if err := opts.validate(ResourceBlobStore); err != nil {
+		return nil, err
+	}
+	blobStores, err := c.getBlobStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []BlobStore
+	for _, bs := range blobStores {
+		if opts.match(bs.Name, bs.Type, "") {
+			result = append(result, bs)
+		}
+	}
+	return result, nil
+}
+
+// GetBlobStore returns a blob store with its usage, its quota and the path or the bucket depending on its type
+func (c *Client) GetBlobStore(ctx context.Context, name string) (BlobStore, error) {
+	blobStore, err := c.getBlobStore(ctx, name)
+	if err != nil {
+		return blobStore, err
+	}
+	switch blobStore.Type {
+	case BlobStoreFile, BlobStoreS3:
+		notFound := newError(ErrBlobStoreNotFound, blobStoreNotFoundInfo, name)
+		if err := c.doREST(ctx, "GET", blobStoreTypePath(blobStore.Type, name), nil, &blobStore, notFound); err != nil {
+			return blobStore, err
+		}
+	}
+	return blobStore, nil
+}
+
+// CreateFileBlobStore creates a blob store in a directory of the nexus server. A relative path is resolved
+// against the blobs directory of nexus. quota is optional
+func (c *Client) CreateFileBlobStore(ctx context.Context, name, path string, quota *SoftQuota) error {
+	if name == "" || path == "" {
+		return newError(ErrInvalidInput, blobStoreRequiredInfo)
+	}
+	if err := validateSoftQuota(quota); err != nil {
+		return err
+	}
+	blobStore := BlobStore{Name: name, Type: BlobStoreFile, Path: path, SoftQuota: quota}
+	return c.createBlobStore(ctx, blobStore, restFileBlobStore{Name: name, Path: path, SoftQuota: quota})
+}
+
+// CreateS3BlobStore creates a blob store in a S3 bucket. quota is optional
+func (c *Client) CreateS3BlobStore(ctx context.Context, name string, bucket S3BucketConfiguration, quota *SoftQuota) error {
+	if name == "" || bucket.Bucket.Name == "" || bucket.Bucket.Region == "" {
+		return newError(ErrInvalidInput, s3BucketRequiredInfo)
+	}
+	if err := validateSoftQuota(quota); err != nil {
+		return err
+	}
+	blobStore := BlobStore{Name: name, Type: BlobStoreS3, SoftQuota: quota, BucketConfiguration: &bucket}
+	return c.createBlobStore(ctx, blobStore, restS3BlobStore{Name: name, SoftQuota: quota, BucketConfiguration: &bucket})
+}
+
+// UpdateBlobStoreQuota sets the soft quota of a file or S3 blob store. A nil quota removes the quota
+func (c *Client) UpdateBlobStoreQuota(ctx context.Context, name string, quota *SoftQuota) error {
+	if name == "" {
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	if err := validateSoftQuota(quota); err != nil {
+		return err
+	}
+	before, err := c.GetBlobStore(ctx, name)
+	if err != nil {
+		return err
+	}
+	after := before
+	after.SoftQuota = quota
+	var body interface{}
+	switch before.Type {
+	case BlobStoreFile:
+		body = restFileBlobStore{Path: before.Path, SoftQuota: quota}
+	case BlobStoreS3:
+		body = restS3BlobStore{Name: name, SoftQuota: quota, BucketConfiguration: before.BucketConfiguration}
+	default:
+		return newError(ErrInvalidInput, blobStoreQuotaNotSupportedInfo, before.Type)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceBlobStore, Name: name, Action: ActionUpdate, Before: before, After: after})
+	} else {
+		notFound := newError(ErrBlobStoreNotFound, blobStoreNotFoundInfo, name)
+		if err := c.doREST(ctx, "PUT", blobStoreTypePath(before.Type, name), body, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(blobStoreUpdatedInfo, name)
+	return nil
+}
+
+// DeleteBlobStore deletes a blob store. Blob stores used by repositories are not deleted
+func (c *Client) DeleteBlobStore(ctx context.Context, name string) error {
+	if name == "" {
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	before, err := c.getBlobStore(ctx, name)
+	if err != nil {
+		return err
+	}
+	usage, err := c.GetBlobStoreUsage(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range usage {
+		if u.BlobStore == name && len(u.Repositories) > 0 {
+			return newError(ErrInvalidInput, blobStoreInUseInfo, name, u.Repositories)
+		}
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceBlobStore, Name: name, Action: ActionDelete, Before: before})
+	} else {
+		notFound := newError(ErrBlobStoreNotFound, blobStoreNotFoundInfo, name)
+		if err := c.doREST(ctx, "DELETE", fmt.Sprintf("%s/%s", blobStoresPath, url.PathEscape(name)), nil, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(blobStoreDeletedInfo, name)
+	return nil
+}
+
+// GetBlobStoreUsage returns the repositories using each blob store sorted by blob store.
+// Blob stores which are not used have no repositories
+func (c *Client) GetBlobStoreUsage(ctx context.Context) ([]BlobStoreUsage, error) {
+	blobStores, err := c.getBlobStores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repositories, err := c.getRepositoriesWithAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string][]string)
+	for _, bs := range blobStores {
+		used[bs.Name] = nil
+	}
+	for _, r := range repositories {
+		blobStoreName := r.Attributes.Storage.BlobStoreName
+		used[blobStoreName] = append(used[blobStoreName], r.Name)
+	}
+	var usage []BlobStoreUsage
+	for _, name := range sortedKeys(used) {
+		usage = append(usage, BlobStoreUsage{BlobStore: name, Repositories: used[name]})
+	}
+	return usage, nil
+}
+
+func (c *Client) createBlobStore(ctx context.Context, blobStore BlobStore, body interface{}) error {
+	exists, err := c.blobStoreExists(ctx, blobStore.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrBlobStoreExists, blobStoreExistsInfo, blobStore.Name)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceBlobStore, Name: blobStore.Name, Action: ActionCreate, After: blobStore})
+	} else if err := c.doREST(ctx, "POST", blobStoreTypePath(blobStore.Type, ""), body, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(blobStoreCreatedInfo, blobStore.Name)
+	return nil
+}
+
+func (c *Client) getBlobStores(ctx context.Context) ([]BlobStore, error) {
+	if err := c.requireFeature(ctx, FeatureRESTBlobStores); err != nil {
+		return nil, err
+	}
+	var blobStores []BlobStore
+	if err := c.doREST(ctx, "GET", blobStoresPath, nil, &blobStores, nil); err != nil {
+		return nil, err
+	}
+	return blobStores, nil
+}
+
+func (c *Client) getBlobStore(ctx context.Context, name string) (BlobStore, error) {
+	if name == "" {
+		return BlobStore{}, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	blobStores, err := c.getBlobStores(ctx)
+	if err != nil {
+		return BlobStore{}, err
+	}
+	for _, bs := range blobStores {
+		if bs.Name == name {
+			return bs, nil
+		}
+	}
+	return BlobStore{}, newError(ErrBlobStoreNotFound, blobStoreNotFoundInfo, name)
+}
+
+func (c *Client) blobStoreExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.getBlobStore(ctx, name)
+	if errors.Is(err, ErrBlobStoreNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// validateBlobStore checks that the blob store of a repository exists.
+// The check is skipped on versions of nexus without the blob store endpoints
+func (c *Client) validateBlobStore(ctx context.Context, name string) error {
+	exists, err := c.blobStoreExists(ctx, name)
+	if errors.Is(err, ErrNotSupported) {
+		if c.debug {
+			c.logger.Printf(blobStoreNotCheckedInfo, name, err)
+		}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrBlobStoreNotFound, blobStoreNotFoundInfo, name)
+	}
+	return nil
+}
+
+func validateSoftQuota(quota *SoftQuota) error {
+	if quota == nil {
+		return nil
+	}
+	if !entryExists(QuotaTypes, quota.Type) {
+		return newError(ErrInvalidInput, quotaTypeNotValidInfo, quota.Type, QuotaTypes)
+	}
+	if quota.Limit <= 0 {
+		return newError(ErrInvalidInput, quotaLimitNotValidInfo, quota.Limit)
+	}
+	return nil
+}
+
+// blobStoreTypePath returns the REST path of the blob stores of a type eg: v1/blobstores/s3/name
+func blobStoreTypePath(blobStoreType, name string) string {
+	path := fmt.Sprintf("%s/%s", blobStoresPath, strings.ToLower(blobStoreType))
+	if name != "" {
+		path = fmt.Sprintf("%s/%s", path, url.PathEscape(name))
+	}
+	return path
+}
@@ -0,0 +1,151 @@
+package nxrm
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	EditionOSS = "OSS"
+	EditionPro = "PRO"
+)
+
+// Feature is a part of the nexus API which is not available on every version or edition
+type Feature string
+
+const (
+	// FeatureScriptAPI is the script API, which is disabled by default from nexus 3.21.2
+	FeatureScriptAPI Feature = "script-api"
+	// FeatureRESTRepositories are the REST endpoints to create and update repositories per format
+	FeatureRESTRepositories Feature = "rest-repositories"
+	// FeatureRESTSecurity are the REST endpoints to manage content selectors, privileges and roles
+	FeatureRESTSecurity Feature = "rest-security"
+	// FeatureRESTBlobStores are the REST endpoints to manage file and S3 blob stores
+	FeatureRESTBlobStores Feature = "rest-blob-stores"
+	// FeaturePro are the features of the Pro edition
+	FeaturePro Feature = "pro"
+)
+
+// serverHeaderRegex matches a server header like Nexus/3.29.2-02 (OSS)
+var serverHeaderRegex = regexp.MustCompile(`Nexus/([0-9][0-9.\-]*)\s*\((\w+)\)`)
+
+// Capabilities describes the version, the edition and the features of a nexus instance
+type Capabilities struct {
+	Version  string           `json:"version"`
+	Edition  string           `json:"edition"`
+	Features map[Feature]bool `json:"features"`
+}
+
+// Has checks if a feature is available
+func (caps Capabilities) Has(feature Feature) bool {
+	return caps.Features[feature]
+}
+
+// AtLeast checks if the version of nexus is greater than or equal to version eg: 3.21.2.
+// An unknown version is never at least a version
+func (caps Capabilities) AtLeast(version string) bool {
+	if caps.Version == "" {
+		return false
+	}
+	current, minimum := versionNumbers(caps.Version), versionNumbers(version)
+	for i := 0; i < len(minimum); i++ {
+		if i >= len(current) {
+			return false
+		}
+		if current[i] != minimum[i] {
+			return current[i] > minimum[i]
+		}
+	}
+	return true
+}
+
+// capabilitiesCache keeps the capabilities of the server once probed
+type capabilitiesCache struct {
+	mu   sync.Mutex
+	caps *Capabilities
+}
+
+// Capabilities probes the version, the edition and the features of the nexus instance.
+// The result is cached by the client
+func (c *Client) Capabilities(ctx context.Context) (Capabilities, error) {
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+	if c.capabilities.caps != nil {
+		return *c.capabilities.caps, nil
+	}
+	caps, err := c.probeCapabilities(ctx)
+	if err != nil {
+		return caps, err
+	}
+	if c.debug {
+		c.logger.Printf(capabilitiesDetectedInfo, caps.Version, caps.Edition, caps.Features)
+	}
+	c.capabilities.caps = &caps
+	return caps, nil
+}
+
+// requireFeature returns ErrNotSupported when a feature is not available on the nexus instance
+func (c *Client) requireFeature(ctx context.Context, feature Feature) error {
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+	if !caps.Has(feature) {
+		return newError(ErrNotSupported, featureNotSupportedInfo, feature, caps.Version, caps.Edition)
+	}
+	return nil
+}
+
+func (c *Client) probeCapabilities(ctx context.Context) (Capabilities, error) {
+	caps := Capabilities{Features: make(map[Feature]bool)}
+
+	url := fmt.Sprintf("%s/%s/%s", c.baseURL, apiBase, statusPath)
+	respBody, status, header, err := c.doRequestWithHeader(ctx, "GET", url, RequestBody{})
+	if err != nil {
+		return caps, err
+	}
+	if status != successStatus {
+		return caps, newAPIError("GET", url, status, respBody)
+	}
+	if m := serverHeaderRegex.FindStringSubmatch(header.Get("Server")); m != nil {
+		caps.Version, caps.Edition = m[1], strings.ToUpper(m[2])
+	}
+	caps.Features[FeaturePro] = caps.Edition == EditionPro
+
+	// updating a script which does not exist returns 404 when the script API is enabled and 410 when it is disabled
+	url = fmt.Sprintf("%s/%s/%s/%s", c.baseURL, apiBase, scriptAPI, probeScriptName)
+	_, status, err = c.doRequest(ctx, "PUT", url, RequestBody{Json: []byte(fmt.Sprintf(`{"name":%q,"type":"groovy","content":""}`, probeScriptName))})
+	if err != nil {
+		return caps, err
+	}
+	caps.Features[FeatureScriptAPI] = status == notFoundStatus
+
+	url = fmt.Sprintf("%s/%s/%s", c.baseURL, apiBase, rolesPath)
+	_, status, err = c.doRequest(ctx, "GET", url, RequestBody{})
+	if err != nil {
+		return caps, err
+	}
+	caps.Features[FeatureRESTSecurity] = status == successStatus
+
+	// the REST endpoints per format were added in 3.20, an unknown version is assumed to be recent when the security endpoints exist
+	caps.Features[FeatureRESTRepositories] = caps.AtLeast("3.20") || (caps.Version == "" && caps.Features[FeatureRESTSecurity])
+	caps.Features[FeatureRESTBlobStores] = caps.Features[FeatureRESTRepositories]
+	return caps, nil
+}
+
+// versionNumbers returns the numbers of a version like 3.29.2-02
+func versionNumbers(version string) []int {
+	var numbers []int
+	for _, part := range strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '-' }) {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
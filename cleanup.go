@@ -0,0 +1,282 @@
+package nxrm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+)
+
+const (
+	// CleanupAllFormats is the format of the cleanup policies which apply to the repositories of every format
+	CleanupAllFormats = "ALL_FORMATS"
+
+	ResourceCleanupPolicy = "cleanup-policy"
+)
+
+// CleanupPolicy removes the components of the repositories it is attached to when they match every criteria which
+// is set. The number of days criteria are not set when 0
+type CleanupPolicy struct {
+	Name   string `json:"name"`
+	Notes  string `json:"notes,omitempty"`
+	Format string `json:"format"`
+	// CriteriaLastBlobUpdated matches the components which were published or updated more than this number of days ago
+	CriteriaLastBlobUpdated int `json:"criteriaLastBlobUpdated,omitempty"`
+	// CriteriaLastDownloaded matches the components which were not downloaded for more than this number of days
+	CriteriaLastDownloaded int `json:"criteriaLastDownloaded,omitempty"`
+	// CriteriaReleaseType matches either the releases or the prereleases, one of CleanupReleaseTypes
+	CriteriaReleaseType string `json:"criteriaReleaseType,omitempty"`
+	// CriteriaAssetRegex matches the components which have an asset whose path matches the regular expression
+	CriteriaAssetRegex string `json:"criteriaAssetRegex,omitempty"`
+}
+
+// ListCleanupPolicies prints the details of a cleanup policy when a name is provided,
+// otherwise prints the names of all the cleanup policies
+func (c *Client) ListCleanupPolicies(ctx context.Context, name string) error {
+	if name != "" {
+		policy, err := c.GetCleanupPolicy(ctx, name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Name: %s\nFormat: %s\nLast blob updated: %d days\nLast downloaded: %d days\nRelease type: %s\nAsset regex: %s\n",
+			policy.Name, policy.Format, policy.CriteriaLastBlobUpdated, policy.CriteriaLastDownloaded, policy.CriteriaReleaseType, policy.CriteriaAssetRegex)
+		return nil
+	}
+	policies, err := c.GetCleanupPolicies(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		fmt.Println(p.Name)
+	}
+	fmt.Printf("Number of cleanup policies : %d\n", len(policies))
+	return nil
+}
+
+// GetCleanupPolicies returns the cleanup policies matching the options.
+// The policies of every format match any format
+func (c *Client) GetCleanupPolicies(ctx context.Context, opts ListOptions) ([]CleanupPolicy, error) {
+	if err := opts.validate(ResourceCleanupPolicy); err != nil {
+		return nil, err
+	}
+	policies, err := c.getCleanupPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []CleanupPolicy
+	for _, p := range policies {
+		format := p.Format
+		if format == CleanupAllFormats {
+			format = opts.Format
+		}
+		if opts.match(p.Name, "", format) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+// GetCleanupPolicy returns a cleanup policy
+func (c *Client) GetCleanupPolicy(ctx context.Context, name string) (CleanupPolicy, error) {
+	if name == "" {
+		return CleanupPolicy{}, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	var policy CleanupPolicy
+	notFound := newError(ErrCleanupPolicyNotFound, cleanupPolicyNotFoundInfo, name)
+	if err := c.doREST(ctx, "GET", fmt.Sprintf("%s/%s", cleanupPoliciesPath, url.PathEscape(name)), nil, &policy, notFound); err != nil {
+		return CleanupPolicy{}, err
+	}
+	return policy, nil
+}
+
+// CreateCleanupPolicy creates a cleanup policy. An empty format creates a policy for every format
+func (c *Client) CreateCleanupPolicy(ctx context.Context, policy CleanupPolicy) error {
+	policy, err := validateCleanupPolicy(policy)
+	if err != nil {
+		return err
+	}
+	exists, err := c.cleanupPolicyExists(ctx, policy.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrCleanupPolicyExists, cleanupPolicyExistsInfo, policy.Name)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceCleanupPolicy, Name: policy.Name, Action: ActionCreate, After: policy})
+	} else if err := c.doREST(ctx, "POST", cleanupPoliciesPath, policy, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(cleanupPolicyCreatedInfo, policy.Name)
+	return nil
+}
+
+// UpdateCleanupPolicy replaces the notes and the criteria of a cleanup policy
+func (c *Client) UpdateCleanupPolicy(ctx context.Context, policy CleanupPolicy) error {
+	policy, err := validateCleanupPolicy(policy)
+	if err != nil {
+		return err
+	}
+	before, err := c.GetCleanupPolicy(ctx, policy.Name)
+	if err != nil {
+		return err
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceCleanupPolicy, Name: policy.Name, Action: ActionUpdate, Before: before, After: policy})
+	} else {
+		notFound := newError(ErrCleanupPolicyNotFound, cleanupPolicyNotFoundInfo, policy.Name)
+		if err := c.doREST(ctx, "PUT", fmt.Sprintf("%s/%s", cleanupPoliciesPath, url.PathEscape(policy.Name)), policy, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(cleanupPolicyUpdatedInfo, policy.Name)
+	return nil
+}
+
+// DeleteCleanupPolicy deletes a cleanup policy. Policies attached to repositories are not deleted
+func (c *Client) DeleteCleanupPolicy(ctx context.Context, name string) error {
+	before, err := c.GetCleanupPolicy(ctx, name)
+	if err != nil {
+		return err
+	}
+	repositories, err := c.getRepositoriesWithAttributes(ctx)
+	if err != nil {
+		return err
+	}
+	var used []string
+	for _, r := range repositories {
+		if entryExists(r.Attributes.Cleanup.PolicyNames, name) {
+			used = append(used, r.Name)
+		}
+	}
+	if len(used) > 0 {
+		return newError(ErrInvalidInput, cleanupPolicyInUseInfo, name, used)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceCleanupPolicy, Name: name, Action: ActionDelete, Before: before})
+	} else {
+		notFound := newError(ErrCleanupPolicyNotFound, cleanupPolicyNotFoundInfo, name)
+		if err := c.doREST(ctx, "DELETE", fmt.Sprintf("%s/%s", cleanupPoliciesPath, url.PathEscape(name)), nil, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(cleanupPolicyDeletedInfo, name)
+	return nil
+}
+
+// SetCleanupPolicy attaches a cleanup policy to a hosted or proxy repository, replacing its current policy.
+// The policy must apply to the format of the repository
+func (c *Client) SetCleanupPolicy(ctx context.Context, repoName, policyName string) error {
+	if repoName == "" || policyName == "" {
+		return newError(ErrInvalidInput, setCleanupPolicyRequiredInfo)
+	}
+	repository, err := c.getRepository(ctx, repoName)
+	if err != nil {
+		return err
+	}
+	if repositoryType(repository) == "group" {
+		return newError(ErrInvalidInput, cleanupGroupRepoInfo, repoName)
+	}
+	policy, err := c.GetCleanupPolicy(ctx, policyName)
+	if err != nil {
+		return err
+	}
+	if policy.Format != CleanupAllFormats && policy.Format != repository.Format {
+		return newError(ErrInvalidInput, cleanupPolicyFormatInfo, policyName, policy.Format, repoName, repository.Format)
+	}
+	return c.UpdateRepository(ctx, repoName, func(attributes *Attributes) {
+		attributes.Cleanup.PolicyNames = []string{policyName}
+	})
+}
+
+// RemoveCleanupPolicy detaches the cleanup policy of a repository
+func (c *Client) RemoveCleanupPolicy(ctx context.Context, repoName string) error {
+	return c.UpdateRepository(ctx, repoName, func(attributes *Attributes) {
+		attributes.Cleanup = Cleanup{}
+	})
+}
+
+// GetRepositoriesWithoutCleanupPolicy returns the hosted and proxy repositories without a cleanup policy sorted by name
+func (c *Client) GetRepositoriesWithoutCleanupPolicy(ctx context.Context) ([]Repository, error) {
+	repositories, err := c.getRepositoriesWithAttributes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Repository
+	for _, r := range repositories {
+		if repositoryType(r) != "group" && len(r.Attributes.Cleanup.PolicyNames) == 0 {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) getCleanupPolicies(ctx context.Context) ([]CleanupPolicy, error) {
+	var policies []CleanupPolicy
+	notSupported := newError(ErrNotSupported, cleanupPoliciesNotSupportedInfo)
+	if err := c.doREST(ctx, "GET", cleanupPoliciesPath, nil, &policies, notSupported); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+func (c *Client) cleanupPolicyExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.GetCleanupPolicy(ctx, name)
+	if errors.Is(err, ErrCleanupPolicyNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// getRepositoriesWithAttributes returns every repository with its attributes sorted by name
+func (c *Client) getRepositoriesWithAttributes(ctx context.Context) ([]Repository, error) {
+	summaries, err := c.getRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var repositories []Repository
+	for _, r := range summaries {
+		repository, err := c.getRepository(ctx, r.Name)
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, repository)
+	}
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].Name < repositories[j].Name })
+	return repositories, nil
+}
+
+// validateCleanupPolicy validates a cleanup policy and returns it with its format normalised
+func validateCleanupPolicy(policy CleanupPolicy) (CleanupPolicy, error) {
+	if policy.Name == "" {
+		return policy, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	if policy.Format == "" || policy.Format == CleanupAllFormats {
+		policy.Format = CleanupAllFormats
+	} else {
+		format, err := validateRepositoryFormat(policy.Format)
+		if err != nil {
+			return policy, err
+		}
+		policy.Format = format
+	}
+	if policy.CriteriaLastBlobUpdated < 0 || policy.CriteriaLastDownloaded < 0 {
+		return policy, newError(ErrInvalidInput, cleanupDaysNotValidInfo)
+	}
+	if policy.CriteriaReleaseType != "" && !entryExists(CleanupReleaseTypes, policy.CriteriaReleaseType) {
+		return policy, newError(ErrInvalidInput, cleanupReleaseTypeNotValidInfo, policy.CriteriaReleaseType, CleanupReleaseTypes)
+	}
+	if policy.CriteriaAssetRegex != "" {
+		if _, err := regexp.Compile(policy.CriteriaAssetRegex); err != nil {
+			return policy, newError(ErrInvalidInput, cleanupAssetRegexNotValidInfo, policy.CriteriaAssetRegex, err)
+		}
+	}
+	if policy.CriteriaLastBlobUpdated == 0 && policy.CriteriaLastDownloaded == 0 && policy.CriteriaReleaseType == "" && policy.CriteriaAssetRegex == "" {
+		return policy, newError(ErrInvalidInput, cleanupCriteriaRequiredInfo)
+	}
+	return policy, nil
+}
@@ -0,0 +1,177 @@
+package nxrm
+
+import (
+	"crypto/tls"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the timeout applied to every request made to Nexus unless set using WithTimeout
+const DefaultTimeout = 60 * time.Second
+
+// Logger is the interface used by the Client to print informational, debug and verbose messages.
+// *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Client is a Nexus repository manager 3 client.
+// A Client holds its own connection details, so several clients talking to different
+// Nexus instances can be used in the same process.
+type Client struct {
+	baseURL             string
+	user                AuthUserStruct
+	httpClient          *http.Client
+	timeout             time.Duration
+	retryPolicy         RetryPolicy
+	logger              Logger
+	scriptsDir          string
+	backendType         Backend
+	resolver            backendResolver
+	capabilities        capabilitiesCache
+	plan                *Plan
+	verbose             bool
+	debug               bool
+	skipTLSVerification bool
+	realmWarnings       bool
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets the base url of the Nexus instance eg: https://nexus.example.com
+func WithBaseURL(url string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithCredentials sets the credentials used to authenticate against Nexus
+func WithCredentials(username, password string) Option {
+	return func(c *Client) {
+		c.user = AuthUserStruct{Username: username, Password: password}
+	}
+}
+
+// WithConnDetails sets the base url and the credentials from stored connection details
+func WithConnDetails(conf ConnDetails) Option {
+	return func(c *Client) {
+		WithBaseURL(conf.NexusURL)(c)
+		WithCredentials(conf.Username, conf.Password)(c)
+	}
+}
+
+// WithHTTPClient sets the http client used to make requests to Nexus
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout applied to every request made to Nexus.
+// The timeout is combined with the deadline of the context passed to the call, the earliest one wins.
+// A timeout of 0 disables the default timeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry the requests which failed with a transient error.
+// DefaultRetryPolicy is used by default, use NoRetry to disable the retries
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithLogger sets the logger used by the client
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithScriptsDir sets a directory containing groovy scripts named <script-name>.groovy.
+// A script found in the directory overrides the script embedded in the library
+func WithScriptsDir(dir string) Option {
+	return func(c *Client) {
+		c.scriptsDir = dir
+	}
+}
+
+// WithBackend selects the API used to manage repositories, content selectors, privileges and roles.
+// BackendScript is used by default
+func WithBackend(backend Backend) Option {
+	return func(c *Client) {
+		c.backendType = backend
+	}
+}
+
+// WithDryRun runs the validations and the lookups of the mutating operations but records the changes in plan
+// instead of making them in nexus. The lookups see the state of nexus, not the changes recorded in the plan
+func WithDryRun(plan *Plan) Option {
+	return func(c *Client) {
+		c.plan = plan
+	}
+}
+
+// WithVerbose prints the request and response details of every call made to Nexus
+func WithVerbose(verbose bool) Option {
+	return func(c *Client) {
+		c.verbose = verbose
+	}
+}
+
+// WithDebug prints debug messages
+func WithDebug(debug bool) Option {
+	return func(c *Client) {
+		c.debug = debug
+	}
+}
+
+// WithSkipTLSVerification disables the verification of the server certificate.
+// The option has no effect when a http client is provided using WithHTTPClient
+func WithSkipTLSVerification(skip bool) Option {
+	return func(c *Client) {
+		c.skipTLSVerification = skip
+	}
+}
+
+// WithRealmWarnings logs a warning when a repository is created and the realm required by its format is not active
+// eg: the docker bearer token realm for the anonymous pulls of docker repositories
+func WithRealmWarnings(warn bool) Option {
+	return func(c *Client) {
+		c.realmWarnings = warn
+	}
+}
+
+// NewClient creates a new Client configured with the provided options
+func NewClient(opts ...Option) *Client {
+	c := &Client{timeout: DefaultTimeout, retryPolicy: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logger == nil {
+		c.logger = log.New(os.Stderr, "", log.LstdFlags)
+	}
+	if c.plan != nil {
+		c.logger = dryRunLogger{c.logger}
+	}
+	if c.httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if c.skipTLSVerification {
+			transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		}
+		c.httpClient = &http.Client{Transport: transport}
+	}
+	return c
+}
+
+// BaseURL returns the base url of the Nexus instance the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
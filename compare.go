@@ -0,0 +1,222 @@
+package nxrm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	DiffOnlyLeft  = "only-left"
+	DiffOnlyRight = "only-right"
+	DiffChanged   = "changed"
+)
+
+// CompareOptions configures CompareInstances
+type CompareOptions struct {
+	// IncludeReadOnly also compares the read only privileges and roles which are built in nexus
+	IncludeReadOnly bool
+}
+
+// FieldDiff is a field of a resource which differs between two instances eg: attributes.storage.writePolicy
+type FieldDiff struct {
+	Field string      `json:"field"`
+	Left  interface{} `json:"left"`
+	Right interface{} `json:"right"`
+}
+
+// ResourceDiff is a resource which is missing on one side or whose fields differ
+type ResourceDiff struct {
+	Resource string      `json:"resource"`
+	Name     string      `json:"name"`
+	Status   string      `json:"status"`
+	Fields   []FieldDiff `json:"fields,omitempty"`
+}
+
+// ConfigDiff lists the differences between the configurations of two instances
+type ConfigDiff struct {
+	Left  string         `json:"left"`
+	Right string         `json:"right"`
+	Diffs []ResourceDiff `json:"diffs"`
+}
+
+// Equal returns true when both configurations are the same
+func (d ConfigDiff) Equal() bool {
+	return len(d.Diffs) == 0
+}
+
+// Report returns a human readable report of the differences
+func (d ConfigDiff) Report() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Comparing %s with %s\n", d.Left, d.Right)
+	if d.Equal() {
+		sb.WriteString("No differences\n")
+		return sb.String()
+	}
+	for _, rd := range d.Diffs {
+		switch rd.Status {
+		case DiffOnlyLeft:
+			fmt.Fprintf(&sb, "%s %q only exists in %s\n", rd.Resource, rd.Name, d.Left)
+		case DiffOnlyRight:
+			fmt.Fprintf(&sb, "%s %q only exists in %s\n", rd.Resource, rd.Name, d.Right)
+		default:
+			fmt.Fprintf(&sb, "%s %q differs\n", rd.Resource, rd.Name)
+			for _, fd := range rd.Fields {
+				fmt.Fprintf(&sb, "  %s : %v != %v\n", fd.Field, fd.Left, fd.Right)
+			}
+		}
+	}
+	fmt.Fprintf(&sb, "Number of differences : %d\n", len(d.Diffs))
+	return sb.String()
+}
+
+func (d ConfigDiff) Header() []string {
+	return []string{"RESOURCE", "NAME", "STATUS", "FIELD", d.Left, d.Right}
+}
+
+func (d ConfigDiff) Rows() [][]string {
+	var rows [][]string
+	for _, rd := range d.Diffs {
+		if len(rd.Fields) == 0 {
+			rows = append(rows, []string{rd.Resource, rd.Name, rd.Status, "", "", ""})
+		}
+		for _, fd := range rd.Fields {
+			rows = append(rows, []string{rd.Resource, rd.Name, rd.Status, fd.Field, fmt.Sprint(fd.Left), fmt.Sprint(fd.Right)})
+		}
+	}
+	return rows
+}
+
+// CompareInstances compares the repositories, content selectors, privileges and roles of two nexus instances.
+// Passwords are not compared as nexus does not return them
+func CompareInstances(ctx context.Context, left, right *Client, opts CompareOptions) (ConfigDiff, error) {
+	exportOpts := ExportOptions{IncludeReadOnly: opts.IncludeReadOnly}
+	leftSpec, err := left.Snapshot(ctx, exportOpts)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	rightSpec, err := right.Snapshot(ctx, exportOpts)
+	if err != nil {
+		return ConfigDiff{}, err
+	}
+	diff, err := CompareSpecs(leftSpec, rightSpec)
+	if err != nil {
+		return diff, err
+	}
+	diff.Left, diff.Right = left.BaseURL(), right.BaseURL()
+	return diff, nil
+}
+
+// CompareSpecs compares two specs, eg: loaded from exported directories
+func CompareSpecs(left, right Spec) (ConfigDiff, error) {
+	diff := ConfigDiff{Left: "left", Right: "right"}
+	kinds := []struct {
+		resource  string
+		resources func(spec Spec) map[string]interface{}
+	}{
+		{ResourceRepository, func(spec Spec) map[string]interface{} {
+			m := make(map[string]interface{})
+			for _, r := range spec.Repositories {
+				m[r.Name] = r
+			}
+			return m
+		}},
+		{ResourceSelector, func(spec Spec) map[string]interface{} {
+			m := make(map[string]interface{})
+			for _, cs := range spec.ContentSelectors {
+				m[cs.Name] = cs
+			}
+			return m
+		}},
+		{ResourcePrivilege, func(spec Spec) map[string]interface{} {
+			m := make(map[string]interface{})
+			for _, p := range spec.Privileges {
+				m[p.Name] = p
+			}
+			return m
+		}},
+		{ResourceRole, func(spec Spec) map[string]interface{} {
+			m := make(map[string]interface{})
+			for _, r := range spec.Roles {
+				m[r.RoleID] = r
+			}
+			return m
+		}},
+	}
+	for _, kind := range kinds {
+		leftResources, rightResources := kind.resources(left), kind.resources(right)
+		names := sortedKeys(leftResources)
+		for _, name := range sortedKeys(rightResources) {
+			if _, ok := leftResources[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			l, inLeft := leftResources[name]
+			r, inRight := rightResources[name]
+			switch {
+			case !inRight:
+				diff.Diffs = append(diff.Diffs, ResourceDiff{Resource: kind.resource, Name: name, Status: DiffOnlyLeft})
+			case !inLeft:
+				diff.Diffs = append(diff.Diffs, ResourceDiff{Resource: kind.resource, Name: name, Status: DiffOnlyRight})
+			default:
+				fields, err := compareFields(l, r)
+				if err != nil {
+					return diff, err
+				}
+				if len(fields) > 0 {
+					diff.Diffs = append(diff.Diffs, ResourceDiff{Resource: kind.resource, Name: name, Status: DiffChanged, Fields: fields})
+				}
+			}
+		}
+	}
+	return diff, nil
+}
+
+// compareFields returns the fields which differ between two resources sorted by field
+func compareFields(left, right interface{}) ([]FieldDiff, error) {
+	var leftFields, rightFields map[string]interface{}
+	if err := convert(left, &leftFields); err != nil {
+		return nil, err
+	}
+	if err := convert(right, &rightFields); err != nil {
+		return nil, err
+	}
+	l, r := make(map[string]interface{}), make(map[string]interface{})
+	flattenFields("", leftFields, l)
+	flattenFields("", rightFields, r)
+	var fields []FieldDiff
+	for field, lv := range l {
+		if rv := r[field]; !reflect.DeepEqual(lv, rv) {
+			fields = append(fields, FieldDiff{Field: field, Left: lv, Right: rv})
+		}
+	}
+	for field, rv := range r {
+		if _, ok := l[field]; !ok {
+			fields = append(fields, FieldDiff{Field: field, Left: nil, Right: rv})
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Field < fields[j].Field })
+	return fields, nil
+}
+
+// flattenFields stores the nested fields in out using their path as key eg: attributes.storage.writePolicy.
+// Passwords are left out
+func flattenFields(prefix string, fields map[string]interface{}, out map[string]interface{}) {
+	for key, value := range fields {
+		if key == "password" {
+			continue
+		}
+		if prefix != "" {
+			key = fmt.Sprintf("%s.%s", prefix, key)
+		}
+		if m, ok := value.(map[string]interface{}); ok && len(m) > 0 {
+			flattenFields(key, m, out)
+			continue
+		}
+		out[key] = value
+	}
+}
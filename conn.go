@@ -2,8 +2,8 @@ package nxrm
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
-	"os"
 )
 
 type ConnDetails struct {
@@ -12,30 +12,32 @@ type ConnDetails struct {
 	Password string
 }
 
-func StoreConnectionDetails() {
-	configuration := ConnDetails{NexusURL: NexusURL, Username: AuthUser.Username, Password: AuthUser.Password}
-	configureJson, err := json.Marshal(configuration)
-	logJsonMarshalError(err, jsonMarshalError)
-	writeFile(ConfFileName, configureJson)
+// StoreConnectionDetails writes the connection details to the configuration file
+func StoreConnectionDetails(conf ConnDetails) error {
+	configureJson, err := json.Marshal(conf)
+	if err != nil {
+		return fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	if err := writeFile(ConfFileName, configureJson); err != nil {
+		return err
+	}
 	log.Printf(connDetailsSuccessInfo, ConfFileName)
+	return nil
 }
 
-func getConnectionDetails() ConnDetails {
+// LoadConnectionDetails reads the connection details from the configuration file.
+// The result can be passed to NewClient using WithConnDetails
+func LoadConnectionDetails() (ConnDetails, error) {
 	var conf ConnDetails
-	data := readFile(ConfFileName)
-	err := json.Unmarshal([]byte(data), &conf)
-	logJsonUnmarshalError(err, jsonUnmarshalError)
-	return conf
-}
-
-func SetConnectionDetails() {
-	if fileExists(ConfFileName) {
-		conf := getConnectionDetails()
-		NexusURL = conf.NexusURL
-		AuthUser.Username = conf.Username
-		AuthUser.Password = conf.Password
-	} else {
-		log.Printf(connDetailsEmptyInfo, "nexus3-repository-cli configure")
-		os.Exit(1)
+	if !fileExists(ConfFileName) {
+		return conf, newError(ErrConnDetailsNotSet, connDetailsEmptyInfo, "nexus3-repository-cli configure")
+	}
+	data, err := readFile(ConfFileName)
+	if err != nil {
+		return conf, err
+	}
+	if err := json.Unmarshal([]byte(data), &conf); err != nil {
+		return conf, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
 	}
+	return conf, nil
 }
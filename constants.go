@@ -6,33 +6,73 @@ const (
 	connDetailsEmptyInfo   = "Server connection details are not set...First Run %q to set the connection details\n"
 
 	// API Extensions
-	apiBase        = "service/rest"
-	scriptAPI      = "v1/script"
-	repositoryPath = "v1/repositories"
+	apiBase             = "service/rest"
+	scriptAPI           = "v1/script"
+	repositoryPath      = "v1/repositories"
+	selectorsPath       = "v1/security/content-selectors"
+	privilegesPath      = "v1/security/privileges"
+	rolesPath           = "v1/security/roles"
+	usersPath           = "v1/security/users"
+	statusPath          = "v1/status"
+	blobStoresPath      = "v1/blobstores"
+	cleanupPoliciesPath = "v1/cleanup-policies"
+	routingRulesPath    = "v1/routing-rules"
+	ldapPath            = "v1/security/ldap"
+	realmsPath          = "v1/security/realms"
+	anonymousPath       = "v1/security/anonymous"
+	extDirectPath       = "service/extdirect"
 
 	successStatus   = "200 OK"
 	notFoundStatus  = "404 Not Found"
 	noContentStatus = "204 No Content"
 	foundStatus     = "302 Found"
 
-	// Script Path
-	scriptBasePath = "./scripts/groovy"
+	goneStatus = "410 Gone"
+
+	// Script Path of the embedded scripts
+	scriptBasePath = "scripts/groovy"
 
 	// Error Strings
 	jsonMarshalError   = "JSON Marshal Error"
 	jsonUnmarshalError = "JSON Unmarshal Error"
+	yamlMarshalError   = "YAML Marshal Error"
 	setVerboseInfo     = "There was an error calling the function. Set verbose flag for more information"
 
 	nameRequiredInfo = "name is a required parameter"
 
+	requestRetryInfo = "%s %s failed, attempt %d of %d, retrying in %s\n"
+
+	//backend
+	backendNotValidInfo    = "%q is not a valid backend. Available backends are : %v"
+	backendDetectedInfo    = "Using the %s backend\n"
+	noBackendAvailableInfo = "Neither the REST endpoints nor the script API are available on nexus %s %s"
+
+	//capabilities
+	probeScriptName          = "nxrm-capabilities-probe"
+	capabilitiesDetectedInfo = "Detected nexus %s %s with features %v\n"
+	featureNotSupportedInfo  = "%s is not supported on nexus %s %s"
+	scriptAPIDisabledInfo    = "The script %q cannot be uploaded as the script API is disabled in nexus"
+
+	//spec
+	specNotValidInfo         = "The spec is not valid : %v"
+	specDuplicateInfo        = "The %s %q is defined more than once in the spec"
+	specRepoTypeNotValidInfo = "Repository %q : the type must be one of %v"
+	specRecipeChangedInfo    = "The recipe of the repository %q cannot be changed from %q to %q"
+	specDependencyCycleInfo  = "%q depends on itself"
+
+	//output
+	outputFormatNotValidInfo = "%q is not a valid output format. Available output formats are : %v"
+	notTabularInfo           = "%T cannot be rendered as a table"
+
 	//script
-	scriptAddedInfo       = "The script %q is added to nexus\n"
-	scriptUpdatedInfo     = "The script %q is updated in nexus\n"
-	scriptDeletedInfo     = "The script %q is deleted from nexus\n"
-	scriptRunSuccessInfo  = "The script %q was executed successfully\n"
-	scriptRunNotFoundInfo = "The script %q was not found in nexus. Make sure you add the script to nexus before executing the script\n"
-	scriptExistsInfo      = "The script %q already exists in nexus\n"
-	scriptNotfoundInfo    = "The script %q was not found in nexus\n"
+	scriptAddedInfo        = "The script %q is added to nexus\n"
+	scriptUpdatedInfo      = "The script %q is updated in nexus\n"
+	scriptDeletedInfo      = "The script %q is deleted from nexus\n"
+	scriptRunSuccessInfo   = "The script %q was executed successfully\n"
+	scriptRunNotFoundInfo  = "The script %q was not found in nexus. Make sure you add the script to nexus before executing the script\n"
+	scriptExistsInfo       = "The script %q already exists in nexus\n"
+	scriptNotfoundInfo     = "The script %q was not found in nexus\n"
+	scriptNotAvailableInfo = "The script %q is neither embedded in the library nor available in the scripts directory\n"
 
 	//scripts
 	getRepoScript            = "get-repo"
@@ -40,17 +80,24 @@ const (
 	createProxyRepoScript    = "create-proxy-repo"
 	createGroupRepoScript    = "create-group-repo"
 	updateGroupMembersScript = "update-group-members"
+	updateRepoScript         = "update-repo"
 	deleteRepoScript         = "delete-repo"
+	getSelectorsScript       = "get-content-selectors"
+	createSelectorScript     = "create-content-selector"
+	updateSelectorScript     = "update-content-selector"
+	deleteSelectorScript     = "delete-content-selector"
 	getPrivilegesScript      = "get-privileges"
 	createPrivilegeScript    = "create-privilege"
 	updatePrivilegeScript    = "update-privilege"
 	deletePrivilegeScript    = "delete-privilege"
 	getRoleScript            = "get-roles"
 	createRoleScript         = "create-role"
+	updateRoleScript         = "update-role"
 	deleteRoleScript         = "delete-role"
 
 	//repo
 	RepoFormatNotValidInfo        = "%q is not a valid repository format. Available repository formats are : %v\n"
+	recipeNotValidInfo            = "%q is not a valid recipe. The recipe must end with -hosted, -proxy or -group"
 	repoFormatRequiredInfo        = "format is a required parameter"
 	repoNameFormatRequiredInfo    = "name and format are required parameters to create a repository"
 	proxyRepoRequiredInfo         = "remote-url is a required parameter to create a proxy repository"
@@ -66,6 +113,8 @@ const (
 	repoExistsInfo                = "Repository %q already exists in nexus\n"
 	cannotBeSameRepoInfo          = "Member %q == group %q, cannot add a group repository as a member in the same group\n"
 	proxyCredsNotValidInfo        = "You need to provide both proxy-user and proxy-pass to set credentials to a proxy repository"
+	updateFuncRequiredInfo        = "A function setting the attributes to update is required"
+	proxyPasswordRequiredInfo     = "The proxy repository %q uses credentials, the password must be provided to update it with the REST API which does not return the password"
 	remoteURLNotValidInfo         = "%q is an invalid url. URL must begin with either http:// or https://"
 	notAGroupRepoInfo             = "%q is not a group repository\n"
 	groupMemberInvalidFormatInfo  = "Repository %q is not a %q format repository, hence it cannot be added to the group repository\n"
@@ -75,6 +124,62 @@ const (
 	groupMemberRequiredInfo       = "At least one valid group member should be provided to add to a group repository"
 	groupMemberAddSuccessInfo     = "Member %q is added to the group %q\n"
 	groupMemberRemoveSuccessInfo  = "Member %q is removed from the group %q\n"
+	writePolicyNotValidInfo       = "%q is not a valid write policy. Available write policies are : %v"
+	versionPolicyNotValidInfo     = "%q is not a valid version policy. Available version policies are : %v"
+	layoutPolicyNotValidInfo      = "%q is not a valid layout policy. Available layout policies are : %v"
+	maxAgeNotValidInfo            = "The maximum age of the content and of the metadata must be -1 or more minutes"
+	negativeCacheTTLNotValidInfo  = "%v is not a valid time to live of the negative cache. The time to live must be 0 or more minutes"
+	dockerPortNotValidInfo        = "%v is not a valid port. The port must be between 1 and 65535"
+	formatTypeNotSupportedInfo    = "A %s repository cannot be created for the format %q. Available types are : %v"
+	aptDistributionRequiredInfo   = "The distribution is required to create an apt repository"
+	aptKeypairRequiredInfo        = "The signing keypair is required to create an apt hosted repository"
+	repodataDepthNotValidInfo     = "%d is not a valid repodata depth. The depth must be between 0 and 5"
+	deployPolicyNotValidInfo      = "%q is not a valid deploy policy. Available deploy policies are : %v"
+	nugetVersionNotValidInfo      = "%q is not a valid nuget version. Available versions are : %v"
+
+	//blob store
+	blobStoreRequiredInfo          = "name and path are required parameters to create a file blob store"
+	s3BucketRequiredInfo           = "name, bucket and region are required parameters to create a S3 blob store"
+	blobStoreNotFoundInfo          = "Blob store %q was not found in nexus"
+	blobStoreExistsInfo            = "Blob store %q already exists in nexus"
+	blobStoreInUseInfo             = "Blob store %q cannot be deleted as it is used by the repositories %v"
+	blobStoreCreatedInfo           = "Blob store %q was created in nexus\n"
+	blobStoreUpdatedInfo           = "The quota of the blob store %q was updated\n"
+	blobStoreDeletedInfo           = "Blob store %q was deleted from nexus\n"
+	blobStoreQuotaNotSupportedInfo = "The quota of a %s blob store cannot be updated, only file and S3 blob stores are supported"
+	quotaTypeNotValidInfo          = "%q is not a valid quota type. Available quota types are : %v"
+	quotaLimitNotValidInfo         = "%d is not a valid quota limit. The limit must be more than 0 bytes"
+	blobStoreNotCheckedInfo        = "The blob store %q was not checked : %v\n"
+
+	//cleanup policy
+	cleanupPoliciesNotSupportedInfo = "The cleanup policy endpoints are not available on this nexus instance"
+	cleanupPolicyNotFoundInfo       = "Cleanup policy %q was not found in nexus"
+	cleanupPolicyExistsInfo         = "Cleanup policy %q already exists in nexus"
+	cleanupPolicyInUseInfo          = "Cleanup policy %q cannot be deleted as it is used by the repositories %v"
+	cleanupPolicyCreatedInfo        = "Cleanup policy %q was created in nexus\n"
+	cleanupPolicyUpdatedInfo        = "Cleanup policy %q was updated in nexus\n"
+	cleanupPolicyDeletedInfo        = "Cleanup policy %q was deleted from nexus\n"
+	cleanupDaysNotValidInfo         = "The number of days of the criteria must be 0 or more"
+	cleanupReleaseTypeNotValidInfo  = "%q is not a valid release type. Available release types are : %v"
+	cleanupAssetRegexNotValidInfo   = "%q is not a valid asset regex : %v"
+	cleanupCriteriaRequiredInfo     = "At least one criteria is required to create a cleanup policy"
+	setCleanupPolicyRequiredInfo    = "repo-name and policy-name are required parameters"
+	cleanupGroupRepoInfo            = "%q is a group repository, cleanup policies only apply to hosted and proxy repositories"
+	cleanupPolicyFormatInfo         = "Cleanup policy %q of the format %q cannot be attached to the repository %q of the format %q"
+
+	//routing rule
+	routingRulesNotSupportedInfo   = "The routing rule endpoints are not available on this nexus instance"
+	routingRuleNotFoundInfo        = "Routing rule %q was not found in nexus"
+	routingRuleExistsInfo          = "Routing rule %q already exists in nexus"
+	routingRuleInUseInfo           = "Routing rule %q cannot be deleted as it is used by the repositories %v"
+	routingRuleCreatedInfo         = "Routing rule %q was created in nexus\n"
+	routingRuleUpdatedInfo         = "Routing rule %q was updated in nexus\n"
+	routingRuleDeletedInfo         = "Routing rule %q was deleted from nexus\n"
+	routingRuleModeNotValidInfo    = "%q is not a valid routing rule mode. Available modes are : %v"
+	routingRuleMatcherRequiredInfo = "At least one matcher is required to create a routing rule"
+	routingRuleMatcherNotValidInfo = "%q is not a valid matcher : %v"
+	setRoutingRuleRequiredInfo     = "repo-name and rule-name are required parameters"
+	routingRuleHostedRepoInfo      = "%q is a hosted repository, routing rules only apply to proxy and group repositories"
 
 	//selector
 	contentSelectorType               = "csel"
@@ -87,13 +192,73 @@ const (
 	selectorNotFoundInfo              = "Content selector %q was not found in nexus\n"
 
 	//privilege
-	defaultPrivilegeDescription = "Custom privilege created using the CLI"
-	privilegeNotFoundInfo       = "Privilege %q was not found in nexus\n"
-	privilegeExistsInfo         = "Privilege %q already exists\n"
-	createPrivilegeRequiredInfo = "name, selector-name and repo-name are required parameters"
-	createPrivilegeSuccessInfo  = "Privilege %q is created"
-	updatePrivilegeSuccessInfo  = "Privilege %q is updated"
-	deletePrivilegeSuccessInfo  = "Privilege %q is deleted"
+	defaultPrivilegeDescription     = "Custom privilege created using the CLI"
+	privilegeNotFoundInfo           = "Privilege %q was not found in nexus\n"
+	privilegeExistsInfo             = "Privilege %q already exists\n"
+	createPrivilegeRequiredInfo     = "name, selector-name and repo-name are required parameters"
+	createPrivilegeSuccessInfo      = "Privilege %q is created"
+	updatePrivilegeSuccessInfo      = "Privilege %q is updated"
+	deletePrivilegeSuccessInfo      = "Privilege %q is deleted"
+	privilegeTypeNotValidInfo       = "%q is not a valid privilege type. Available types are : %v"
+	privilegeTypeChangedInfo        = "The type of the privilege %q cannot be changed from %q to %q"
+	privilegePropertiesRequiredInfo = "The %s privileges require the %s"
+	privilegeActionNotValidInfo     = "%q is not a valid action for the %s privileges. Available actions are : %v"
+	privilegeRepositoryNotValidInfo = "%q is not a valid repository for the %s privileges, use * and the format to select every repository of a format"
+	privilegeFormatInfo             = "The privilege %q on the format %q cannot be granted on the repository %q of the format %q"
+
+	//user
+	userIDRequiredInfo        = "user-id is a required parameter"
+	createUserRequiredInfo    = "user-id, first-name, last-name and email are required parameters"
+	updateUserRequiredInfo    = "first-name, last-name and email cannot be removed from a local user"
+	passwordRequiredInfo      = "password is a required parameter"
+	emailNotValidInfo         = "%q is not a valid email address"
+	userStatusNotValidInfo    = "%q is not a valid user status. Available statuses are : %v"
+	userNotFoundInfo          = "User %q was not found in nexus"
+	userExistsInfo            = "User %q already exists in nexus"
+	userNotLocalInfo          = "The password of the user %q cannot be changed as the user is managed by the source %q"
+	userCreatedInfo           = "User %q was created in nexus\n"
+	userUpdatedInfo           = "User %q was updated in nexus\n"
+	userDeletedInfo           = "User %q was deleted from nexus\n"
+	passwordChangedInfo       = "The password of the user %q was changed\n"
+	userRolesRequiredInfo     = "At least one role should be provided"
+	userRoleAlreadyExistsInfo = "Role %q is already granted to the user %q, hence not adding the role again\n"
+	userRoleNotFoundInfo      = "Role %q is not granted to the user %q, hence cannot remove the role from the user\n"
+	userRoleAddedInfo         = "Role %q is granted to the user %q\n"
+	userRoleRemovedInfo       = "Role %q is revoked from the user %q\n"
+
+	//ldap
+	ldapServerRequiredInfo       = "name, host and search-base are required parameters"
+	ldapProtocolNotValidInfo     = "%q is not a valid LDAP protocol. Available protocols are : %v"
+	ldapPortNotValidInfo         = "%d is not a valid port, the port must be between 1 and 65535"
+	ldapAuthSchemeNotValidInfo   = "%q is not a valid LDAP authentication scheme. Available schemes are : %v"
+	ldapCredentialsRequiredInfo  = "The username and the password are required for the authentication scheme %q"
+	ldapUserMappingRequiredInfo  = "The user object class and the user id, real name and email attributes are required"
+	ldapGroupTypeNotValidInfo    = "%q is not a valid LDAP group type. Available group types are : %v"
+	ldapStaticGroupRequiredInfo  = "The group object class and the group id, member and member format attributes are required for static groups"
+	ldapDynamicGroupRequiredInfo = "The user member of attribute is required for dynamic groups"
+	ldapServerNotFoundInfo       = "LDAP server %q was not found in nexus"
+	ldapServerExistsInfo         = "LDAP server %q already exists in nexus"
+	ldapOrderIncompleteInfo      = "The LDAP server %q is missing from the order, every server must be listed"
+	ldapOrderDuplicateInfo       = "The LDAP server %q is listed more than once"
+	ldapServerCreatedInfo        = "LDAP server %q was created in nexus\n"
+	ldapServerUpdatedInfo        = "LDAP server %q was updated in nexus\n"
+	ldapServerDeletedInfo        = "LDAP server %q was deleted from nexus\n"
+	ldapOrderChangedInfo         = "The LDAP servers are queried in the order %v\n"
+	externalRoleSourceInfo       = "%q is not an external source, use CreateRole to create a nexus role"
+	extDirectFailedInfo          = "%s.%s failed : %s"
+
+	//realm
+	realmsRequiredInfo     = "At least one realm should be provided"
+	realmNotValidInfo      = "%q is not an available realm. Available realms are : %v"
+	realmDuplicateInfo     = "The realm %q is listed more than once"
+	realmAlreadyActiveInfo = "Realm %q is already active, hence not adding the realm again\n"
+	realmNotActiveInfo     = "Realm %q is not active, hence cannot remove the realm\n"
+	realmsActivatedInfo    = "The active realms are %v\n"
+	realmInactiveInfo      = "WARNING : the realm %q required by the %s repository %q is not active\n"
+	realmCheckSkippedInfo  = "The realms required by the repository %q were not checked : %v\n"
+
+	//anonymous
+	anonymousAccessUpdatedInfo = "The anonymous access was updated : enabled %t, user %q, realm %q\n"
 
 	//role
 	UpdateActionRequiredInfo = "Update action is a required parameter. Available values = %+q\n"
@@ -0,0 +1,88 @@
+package nxrm
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors returned by the library. Errors returned by the functions wrap one of these values
+// and can be matched using errors.Is
+var (
+	ErrInvalidInput          = errors.New("invalid input")
+	ErrNotSupported          = errors.New("not supported on this nexus version")
+	ErrConnDetailsNotSet     = errors.New("connection details are not set")
+	ErrFileNotFound          = errors.New("file not found")
+	ErrRepositoryNotFound    = errors.New("repository not found")
+	ErrRepositoryExists      = errors.New("repository already exists")
+	ErrScriptNotFound        = errors.New("script not found")
+	ErrScriptExists          = errors.New("script already exists")
+	ErrSelectorNotFound      = errors.New("content selector not found")
+	ErrSelectorExists        = errors.New("content selector already exists")
+	ErrPrivilegeNotFound     = errors.New("privilege not found")
+	ErrPrivilegeExists       = errors.New("privilege already exists")
+	ErrRoleNotFound          = errors.New("role not found")
+	ErrRoleExists            = errors.New("role already exists")
+	ErrBlobStoreNotFound     = errors.New("blob store not found")
+	ErrBlobStoreExists       = errors.New("blob store already exists")
+	ErrCleanupPolicyNotFound = errors.New("cleanup policy not found")
+	ErrCleanupPolicyExists   = errors.New("cleanup policy already exists")
+	ErrRoutingRuleNotFound   = errors.New("routing rule not found")
+	ErrRoutingRuleExists     = errors.New("routing rule already exists")
+	ErrUserNotFound          = errors.New("user not found")
+	ErrUserExists            = errors.New("user already exists")
+	ErrLDAPServerNotFound    = errors.New("LDAP server not found")
+	ErrLDAPServerExists      = errors.New("LDAP server already exists")
+)
+
+// APIError is returned when Nexus responds with an unexpected status.
+// For script runs the status and the message returned by the script are used
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s : unexpected status %q", e.Method, e.URL, e.Status)
+	if e.Body != "" {
+		msg = fmt.Sprintf("%s : %s", msg, e.Body)
+	}
+	return msg
+}
+
+func newAPIError(method, url, status string, body []byte) *APIError {
+	return &APIError{Method: method, URL: url, StatusCode: statusCode(status), Status: status, Body: strings.TrimSpace(string(body))}
+}
+
+// nxrmError carries a detailed message while matching a sentinel error using errors.Is
+type nxrmError struct {
+	err error
+	msg string
+}
+
+func (e *nxrmError) Error() string {
+	return e.msg
+}
+
+func (e *nxrmError) Unwrap() error {
+	return e.err
+}
+
+// newError returns an error with a formatted message that wraps err
+func newError(err error, format string, a ...interface{}) error {
+	return &nxrmError{err: err, msg: strings.TrimSpace(fmt.Sprintf(format, a...))}
+}
+
+// statusCode returns the numeric code of a status like "404 Not Found"
+func statusCode(status string) int {
+	fields := strings.Fields(status)
+	if len(fields) == 0 {
+		return 0
+	}
+	code, _ := strconv.Atoi(fields[0])
+	return code
+}
@@ -0,0 +1,269 @@
+package nxrm
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Directories of the resources in an exported configuration
+const (
+	repositoriesDir = "repositories"
+	selectorsDir    = "content-selectors"
+	privilegesDir   = "privileges"
+	rolesDir        = "roles"
+)
+
+// ExportOptions configures Export
+type ExportOptions struct {
+	// Format of the files, either OutputYAML or OutputJSON. Defaults to OutputYAML
+	Format string
+	// IncludeSecrets keeps the passwords of the proxy repositories, they are removed by default. A spec without the
+	// passwords can be applied to the instance it was exported from as the stored passwords are kept, except when a
+	// proxy repository using credentials is updated with the REST backend which requires its password
+	IncludeSecrets bool
+	// IncludeReadOnly keeps the read only privileges and roles which are built in nexus
+	IncludeReadOnly bool
+}
+
+// fileNameReplacer replaces the characters of a resource name which cannot be used in a file name
+var fileNameReplacer = strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+
+// Snapshot returns the repositories with their attributes, the content selectors, the privileges and the roles
+// of the nexus instance as a spec sorted by name. The privileges of the roles are referenced by name
+func (c *Client) Snapshot(ctx context.Context, opts ExportOptions) (Spec, error) {
+	var spec Spec
+	repositories, err := c.getRepositories(ctx)
+	if err != nil {
+		return spec, err
+	}
+	for _, r := range repositories {
+		repository, err := c.getRepository(ctx, r.Name)
+		if err != nil {
+			return spec, err
+		}
+		// the url depends on the instance
+		repository.URL = ""
+		if !opts.IncludeSecrets {
+			repository.Attributes.Httpclient.Authentication.Password = ""
+		}
+		spec.Repositories = append(spec.Repositories, repository)
+	}
+	sort.Slice(spec.Repositories, func(i, j int) bool { return spec.Repositories[i].Name < spec.Repositories[j].Name })
+
+	spec.ContentSelectors, err = c.getSelectors(ctx)
+	if err != nil {
+		return spec, err
+	}
+	sort.Slice(spec.ContentSelectors, func(i, j int) bool { return spec.ContentSelectors[i].Name < spec.ContentSelectors[j].Name })
+
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return spec, err
+	}
+	privilegeNames := make(map[string]string)
+	for _, p := range privileges {
+		privilegeNames[p.ID] = p.Name
+		if !p.ReadOnly || opts.IncludeReadOnly {
+			spec.Privileges = append(spec.Privileges, p)
+		}
+	}
+	sort.Slice(spec.Privileges, func(i, j int) bool { return spec.Privileges[i].Name < spec.Privileges[j].Name })
+
+	roles, err := c.getRoles(ctx)
+	if err != nil {
+		return spec, err
+	}
+	for _, r := range roles {
+		if r.ReadOnly && !opts.IncludeReadOnly {
+			continue
+		}
+		var names []string
+		for _, id := range r.Privileges {
+			if name, ok := privilegeNames[id]; ok {
+				id = name
+			}
+			names = append(names, id)
+		}
+		sort.Strings(names)
+		r.Privileges = names
+		r.Roles = append([]string(nil), r.Roles...)
+		sort.Strings(r.Roles)
+		spec.Roles = append(spec.Roles, r)
+	}
+	sort.Slice(spec.Roles, func(i, j int) bool { return spec.Roles[i].RoleID < spec.Roles[j].RoleID })
+	return spec, nil
+}
+
+// Export writes the configuration of the nexus instance to dir, one file per resource in a directory per kind
+// of resource eg: repositories/maven-releases.yaml. The exported directory can be loaded using LoadSpecDir
+func (c *Client) Export(ctx context.Context, dir string, opts ExportOptions) (Spec, error) {
+	spec, err := c.Snapshot(ctx, opts)
+	if err != nil {
+		return spec, err
+	}
+	return spec, WriteSpecDir(dir, spec, opts.Format)
+}
+
+// WriteSpecDir writes a spec to dir, one file per resource in a directory per kind of resource.
+// Files left in these directories by a previous export are removed so that deleted resources do not remain
+func WriteSpecDir(dir string, spec Spec, format string) error {
+	if format == "" {
+		format = OutputYAML
+	}
+	if format != OutputYAML && format != OutputJSON {
+		return newError(ErrInvalidInput, outputFormatNotValidInfo, format, []string{OutputYAML, OutputJSON})
+	}
+	files := map[string]map[string]interface{}{
+		repositoriesDir: {},
+		selectorsDir:    {},
+		privilegesDir:   {},
+		rolesDir:        {},
+	}
+	for _, r := range spec.Repositories {
+		files[repositoriesDir][r.Name] = r
+	}
+	for _, cs := range spec.ContentSelectors {
+		files[selectorsDir][cs.Name] = cs
+	}
+	for _, p := range spec.Privileges {
+		files[privilegesDir][p.Name] = p
+	}
+	for _, r := range spec.Roles {
+		files[rolesDir][r.RoleID] = r
+	}
+	for kind, resources := range files {
+		kindDir := filepath.Join(dir, kind)
+		if err := os.MkdirAll(kindDir, 0755); err != nil {
+			return fmt.Errorf("there was an error creating the directory %q : %w", kindDir, err)
+		}
+		if err := removeSpecFiles(kindDir); err != nil {
+			return err
+		}
+		for name, resource := range resources {
+			data, err := marshalSpecFile(resource, format)
+			if err != nil {
+				return err
+			}
+			fileName := filepath.Join(kindDir, fmt.Sprintf("%s.%s", fileNameReplacer.Replace(name), format))
+			if err := writeFile(fileName, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadSpecDir reads a spec from a directory written by Export or WriteSpecDir.
+// The directories of the kinds of resources are optional
+func LoadSpecDir(dir string) (Spec, error) {
+	var spec Spec
+	targets := []struct {
+		kind string
+		add  func(data []byte) error
+	}{
+		{repositoriesDir, func(data []byte) error {
+			var r Repository
+			if err := decodeSpec(data, &r); err != nil {
+				return err
+			}
+			spec.Repositories = append(spec.Repositories, r)
+			return spec.decodeDocumentFields(ResourceRepository, r.Name, data)
+		}},
+		{selectorsDir, func(data []byte) error {
+			var cs ContentSelector
+			if err := decodeSpec(data, &cs); err != nil {
+				return err
+			}
+			spec.ContentSelectors = append(spec.ContentSelectors, cs)
+			return spec.decodeDocumentFields(ResourceSelector, cs.Name, data)
+		}},
+		{privilegesDir, func(data []byte) error {
+			var p Privilege
+			if err := decodeSpec(data, &p); err != nil {
+				return err
+			}
+			spec.Privileges = append(spec.Privileges, p)
+			return spec.decodeDocumentFields(ResourcePrivilege, p.Name, data)
+		}},
+		{rolesDir, func(data []byte) error {
+			var r Role
+			if err := decodeSpec(data, &r); err != nil {
+				return err
+			}
+			spec.Roles = append(spec.Roles, r)
+			return spec.decodeDocumentFields(ResourceRole, r.RoleID, data)
+		}},
+	}
+	for _, target := range targets {
+		fileNames, err := specFiles(filepath.Join(dir, target.kind))
+		if err != nil {
+			return spec, err
+		}
+		for _, fileName := range fileNames {
+			content, err := readFile(fileName)
+			if err != nil {
+				return spec, err
+			}
+			if err := target.add([]byte(content)); err != nil {
+				return spec, fmt.Errorf("%s : %w", fileName, err)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// marshalSpecFile marshals a resource without the fields which are not set
+func marshalSpecFile(resource interface{}, format string) ([]byte, error) {
+	var fields map[string]interface{}
+	if err := convert(resource, &fields); err != nil {
+		return nil, err
+	}
+	fields = setFields(fields)
+	if format == OutputJSON {
+		var sb strings.Builder
+		if err := renderJSON(&sb, fields); err != nil {
+			return nil, err
+		}
+		return []byte(sb.String()), nil
+	}
+	return toYAML(fields)
+}
+
+// specFiles returns the yaml and json files of a directory sorted by name. A missing directory has no files
+func specFiles(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("there was an error reading the directory %q : %w", dir, err)
+	}
+	var fileNames []string
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			if !entry.IsDir() {
+				fileNames = append(fileNames, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(fileNames)
+	return fileNames, nil
+}
+
+func removeSpecFiles(dir string) error {
+	fileNames, err := specFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, fileName := range fileNames {
+		if err := os.Remove(fileName); err != nil {
+			return fmt.Errorf("there was an error removing the file %q : %w", fileName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package nxrm
+
+import (
+	"path"
+	"strings"
+)
+
+// ListOptions filters the resources returned by the Get* functions.
+// Empty fields match every resource
+type ListOptions struct {
+	// Format of a repository eg: maven, npm, docker. Only supported by the repositories and the cleanup policies,
+	// the other resources reject the option
+	Format string
+	// Type of the resource eg: hosted, proxy or group for repositories, the privilege type for privileges,
+	// the selector type for content selectors, the script type for scripts and the source for roles
+	Type string
+	// Name is a pattern matched against the name of the resource (the id for roles).
+	// The pattern syntax is the one of path.Match eg: maven-*
+	Name string
+}
+
+// formatResources are the resources which can be filtered by format
+var formatResources = []string{ResourceRepository, ResourceCleanupPolicy}
+
+// validate checks the name pattern and normalises the repository format. The format is rejected when resource
+// cannot be filtered by format, as no resource would match
+func (o *ListOptions) validate(resource string) error {
+	if _, err := path.Match(o.Name, ""); err != nil {
+		return newError(ErrInvalidInput, "%q is not a valid name pattern : %v", o.Name, err)
+	}
+	if o.Format != "" && !entryExists(formatResources, resource) {
+		return newError(ErrInvalidInput, "The %s resources cannot be filtered by format", resource)
+	}
+	if o.Format != "" {
+		format, err := validateRepositoryFormat(o.Format)
+		if err != nil {
+			return err
+		}
+		o.Format = format
+	}
+	return nil
+}
+
+// match returns true when the name, type and format match the options.
+// validate must be called before match
+func (o ListOptions) match(name, resourceType, format string) bool {
+	if o.Name != "" {
+		if ok, _ := path.Match(o.Name, name); !ok {
+			return false
+		}
+	}
+	if o.Type != "" && !strings.EqualFold(o.Type, resourceType) {
+		return false
+	}
+	if o.Format != "" && o.Format != format {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package nxrm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestListOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     ListOptions
+		resource string
+		format   string
+		wantErr  bool
+	}{
+		{"no options", ListOptions{}, ResourceRole, "", false},
+		{"repository format", ListOptions{Format: "maven"}, ResourceRepository, "maven2", false},
+		{"cleanup policy format", ListOptions{Format: "npm"}, ResourceCleanupPolicy, "npm", false},
+		{"format of a resource without a format", ListOptions{Format: "maven"}, ResourceRole, "", true},
+		{"invalid format", ListOptions{Format: "unknown"}, ResourceRepository, "", true},
+		{"invalid pattern", ListOptions{Name: "["}, ResourceRepository, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			err := opts.validate(tt.resource)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("validate() error = %v, want %v", err, ErrInvalidInput)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+			if opts.Format != tt.format {
+				t.Errorf("validate() format = %q, want %q", opts.Format, tt.format)
+			}
+		})
+	}
+}
+
+func TestListOptionsMatch(t *testing.T) {
+	opts := ListOptions{Name: "maven-*", Type: "hosted", Format: "maven2"}
+	tests := []struct {
+		name, resourceName, resourceType, format string
+		want                                     bool
+	}{
+		{"match", "maven-releases", "HOSTED", "maven2", true},
+		{"name", "npm-releases", "hosted", "maven2", false},
+		{"type", "maven-central", "proxy", "maven2", false},
+		{"format", "maven-releases", "hosted", "npm", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := opts.match(tt.resourceName, tt.resourceType, tt.format); got != tt.want {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,16 +12,50 @@ type RequestBody struct {
 }
 
 var (
-	NexusURL            string
-	AuthUser            AuthUserStruct
-	Verbose             bool
-	Debug               bool
-	SkipTLSVerification bool
-
-	InitialRepoList  = []string{"maven-public", "maven-central", "maven-snapshots", "maven-releases", "nuget-group", "nuget-hosted", "nuget.org-proxy"}
-	NexusScripts     = []string{"get-repo", "create-hosted-repo", "create-proxy-repo", "create-group-repo", "update-group-members", "delete-repo", "get-content-selectors", "create-content-selector", "update-content-selector", "delete-content-selector", "get-privileges", "create-privilege", "update-privilege", "delete-privilege", "get-roles", "create-role", "delete-role"}
-	RepoFormats      = []string{"maven", "npm", "nuget", "bower", "pypi", "raw", "rubygems", "yum", "docker"}
-	RepoType         = []string{"hosted", "proxy", "group"}
-	PrivilegeActions = []string{"read", "write"}
-	UpdateActions    = []string{"add", "remove"}
+	InitialRepoList     = []string{"maven-public", "maven-central", "maven-snapshots", "maven-releases", "nuget-group", "nuget-hosted", "nuget.org-proxy"}
+	NexusScripts        = []string{"get-repo", "create-hosted-repo", "create-proxy-repo", "create-group-repo", "update-group-members", "update-repo", "delete-repo", "get-content-selectors", "create-content-selector", "update-content-selector", "delete-content-selector", "get-privileges", "create-privilege", "update-privilege", "delete-privilege", "get-roles", "create-role", "update-role", "delete-role"}
+	RepoFormats         = []string{"maven", "npm", "nuget", "bower", "pypi", "raw", "rubygems", "yum", "docker", "helm", "go", "apt", "conan", "r", "cocoapods", "conda", "gitlfs", "p2"}
+	RepoType            = []string{"hosted", "proxy", "group"}
+	PrivilegeActions    = []string{"read", "write"}
+	UpdateActions       = []string{"add", "remove"}
+	WritePolicies       = []string{"ALLOW", "ALLOW_ONCE", "DENY"}
+	VersionPolicies     = []string{"RELEASE", "SNAPSHOT", "MIXED"}
+	LayoutPolicies      = []string{"STRICT", "PERMISSIVE"}
+	NugetVersions       = []string{"V2", "V3"}
+	QuotaTypes          = []string{"spaceRemainingQuota", "spaceUsedQuota"}
+	CleanupReleaseTypes = []string{"RELEASES", "PRERELEASES"}
+	RoutingRuleModes    = []string{"ALLOW", "BLOCK"}
+	UserStatuses        = []string{"active", "locked", "disabled", "changepassword"}
+	LDAPProtocols       = []string{"ldap", "ldaps"}
+	LDAPAuthSchemes     = []string{"NONE", "SIMPLE", "DIGEST_MD5", "CRAM_MD5"}
+	LDAPGroupTypes      = []string{"static", "dynamic"}
+	PrivilegeTypes      = []string{"wildcard", "application", "repository-view", "repository-admin", "repository-content-selector", "script"}
 )
+
+// readOnlyScripts only read data from nexus and are safe to retry
+var readOnlyScripts = []string{"get-repo", "get-content-selectors", "get-privileges", "get-roles"}
+
+// privilegeTypeActions lists the actions of the privileges per type, a wildcard privilege has no action
+var privilegeTypeActions = map[string][]string{
+	"wildcard":                    {},
+	"application":                 {"create", "read", "update", "delete", "*"},
+	"repository-view":             {"browse", "read", "edit", "add", "delete", "*"},
+	"repository-admin":            {"browse", "read", "edit", "add", "delete", "*"},
+	"repository-content-selector": {"browse", "read", "edit", "add", "delete", "create", "update", "*"},
+	"script":                      {"browse", "read", "edit", "add", "delete", "run", "*"},
+}
+
+// ldapAuthSchemesXO maps the LDAP authentication schemes of the REST API to the schemes of the extdirect API
+var ldapAuthSchemesXO = map[string]string{"NONE": "none", "SIMPLE": "simple", "DIGEST_MD5": "DIGEST-MD5", "CRAM_MD5": "CRAM-MD5"}
+
+// formatRepoTypes lists the repository types available for the formats which do not support every type
+var formatRepoTypes = map[string][]string{
+	"helm":      {"hosted", "proxy"},
+	"go":        {"proxy", "group"},
+	"apt":       {"hosted", "proxy"},
+	"conan":     {"proxy"},
+	"cocoapods": {"proxy"},
+	"conda":     {"proxy"},
+	"gitlfs":    {"hosted"},
+	"p2":        {"proxy"},
+}
@@ -0,0 +1,416 @@
+package nxrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	LDAPProtocol  = "ldap"
+	LDAPSProtocol = "ldaps"
+
+	LDAPGroupsStatic  = "static"
+	LDAPGroupsDynamic = "dynamic"
+
+	// RoleSourceLDAP is the source of the roles which map a LDAP group
+	RoleSourceLDAP = "LDAP"
+
+	ResourceLDAPServer = "ldap-server"
+)
+
+// LDAPServer is the connection to a LDAP server and the mapping of its users and groups.
+// The servers are queried by ascending order when a user logs in
+type LDAPServer struct {
+	ID    string `json:"id,omitempty"`
+	Name  string `json:"name"`
+	Order int    `json:"order,omitempty"`
+	LDAPConnection
+	LDAPUserMapping
+	LDAPGroupMapping
+}
+
+// LDAPConnection contains the address of a LDAP server and the credentials used to search it.
+// The password is not returned by nexus and must be set to create or update a server
+type LDAPConnection struct {
+	Protocol                    string `json:"protocol"`
+	UseTrustStore               bool   `json:"useTrustStore"`
+	Host                        string `json:"host"`
+	Port                        int    `json:"port"`
+	SearchBase                  string `json:"searchBase"`
+	AuthScheme                  string `json:"authScheme"`
+	AuthRealm                   string `json:"authRealm,omitempty"`
+	AuthUsername                string `json:"authUsername,omitempty"`
+	AuthPassword                string `json:"authPassword,omitempty"`
+	ConnectionTimeoutSeconds    int    `json:"connectionTimeoutSeconds"`
+	ConnectionRetryDelaySeconds int    `json:"connectionRetryDelaySeconds"`
+	MaxIncidentsCount           int    `json:"maxIncidentsCount"`
+}
+
+// LDAPUserMapping maps the entries of a LDAP server to users
+type LDAPUserMapping struct {
+	UserBaseDN                string `json:"userBaseDn,omitempty"`
+	UserSubtree               bool   `json:"userSubtree"`
+	UserObjectClass           string `json:"userObjectClass"`
+	UserLDAPFilter            string `json:"userLdapFilter,omitempty"`
+	UserIDAttribute           string `json:"userIdAttribute"`
+	UserRealNameAttribute     string `json:"userRealNameAttribute"`
+	UserEmailAddressAttribute string `json:"userEmailAddressAttribute"`
+	UserPasswordAttribute     string `json:"userPasswordAttribute,omitempty"`
+}
+
+// LDAPGroupMapping maps the groups of a LDAP server to roles when LDAPGroupsAsRoles is set.
+// Static groups list their members, the groups of the dynamic groups are listed by the users
+type LDAPGroupMapping struct {
+	LDAPGroupsAsRoles     bool   `json:"ldapGroupsAsRoles"`
+	GroupType             string `json:"groupType,omitempty"`
+	GroupBaseDN           string `json:"groupBaseDn,omitempty"`
+	GroupSubtree          bool   `json:"groupSubtree"`
+	GroupObjectClass      string `json:"groupObjectClass,omitempty"`
+	GroupIDAttribute      string `json:"groupIdAttribute,omitempty"`
+	GroupMemberAttribute  string `json:"groupMemberAttribute,omitempty"`
+	GroupMemberFormat     string `json:"groupMemberFormat,omitempty"`
+	UserMemberOfAttribute string `json:"userMemberOfAttribute,omitempty"`
+}
+
+// LDAPUser is a user found by VerifyLDAPUserMapping with the groups it is a member of
+type LDAPUser struct {
+	Username   string   `json:"username"`
+	RealName   string   `json:"realName"`
+	Email      string   `json:"email"`
+	Membership []string `json:"membership"`
+}
+
+// extDirectResponse is the response of the extdirect API used by the nexus UI
+type extDirectResponse struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Result  struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	} `json:"result"`
+}
+
+// NewLDAPServer returns a LDAP server without authentication using the defaults of nexus for the connection
+// and a user mapping for inetOrgPerson entries
+func NewLDAPServer(name, host, searchBase string) LDAPServer {
+	return LDAPServer{
+		Name: name,
+		LDAPConnection: LDAPConnection{
+			Protocol:                    LDAPProtocol,
+			Host:                        host,
+			Port:                        389,
+			SearchBase:                  searchBase,
+			AuthScheme:                  "NONE",
+			ConnectionTimeoutSeconds:    30,
+			ConnectionRetryDelaySeconds: 300,
+			MaxIncidentsCount:           3,
+		},
+		LDAPUserMapping: LDAPUserMapping{
+			UserObjectClass:           "inetOrgPerson",
+			UserIDAttribute:           "uid",
+			UserRealNameAttribute:     "cn",
+			UserEmailAddressAttribute: "mail",
+		},
+	}
+}
+
+// ListLDAPServers prints the details of a LDAP server when a name is provided,
+// otherwise prints the names of all the LDAP servers by order
+func (c *Client) ListLDAPServers(ctx context.Context, name string) error {
+	if name != "" {
+		server, err := c.GetLDAPServer(ctx, name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Name: %s\nOrder: %d\nURL: %s://%s:%d/%s\nAuthentication: %s\n",
+			server.Name, server.Order, server.Protocol, server.Host, server.Port, server.SearchBase, server.AuthScheme)
+		return nil
+	}
+	servers, err := c.GetLDAPServers(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, s := range servers {
+		fmt.Println(s.Name)
+	}
+	fmt.Printf("Number of LDAP servers : %d\n", len(servers))
+	return nil
+}
+
+// GetLDAPServers returns the LDAP servers matching the options by order, the type is matched against the protocol
+func (c *Client) GetLDAPServers(ctx context.Context, opts ListOptions) ([]LDAPServer, error) {
+	if err := opts.validate(ResourceLDAPServer); err != nil {
+		return nil, err
+	}
+	servers, err := c.getLDAPServers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []LDAPServer
+	for _, s := range servers {
+		if opts.match(s.Name, s.Protocol, "") {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// GetLDAPServer returns a LDAP server
+func (c *Client) GetLDAPServer(ctx context.Context, name string) (LDAPServer, error) {
+	if name == "" {
+		return LDAPServer{}, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	var server LDAPServer
+	notFound := newError(ErrLDAPServerNotFound, ldapServerNotFoundInfo, name)
+	if err := c.doSecurity(ctx, "GET", fmt.Sprintf("%s/%s", ldapPath, url.PathEscape(name)), nil, &server, notFound); err != nil {
+		return LDAPServer{}, err
+	}
+	return server, nil
+}
+
+// CreateLDAPServer creates a LDAP server, which is queried after the existing servers
+func (c *Client) CreateLDAPServer(ctx context.Context, server LDAPServer) error {
+	if err := validateLDAPServer(server); err != nil {
+		return err
+	}
+	exists, err := c.ldapServerExists(ctx, server.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrLDAPServerExists, ldapServerExistsInfo, server.Name)
+	}
+	server.ID, server.Order = "", 0
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceLDAPServer, Name: server.Name, Action: ActionCreate, After: withoutLDAPPassword(server)})
+	} else if err := c.doSecurity(ctx, "POST", ldapPath, server, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(ldapServerCreatedInfo, server.Name)
+	return nil
+}
+
+// UpdateLDAPServer replaces the connection and the mapping of a LDAP server. The order is not changed
+func (c *Client) UpdateLDAPServer(ctx context.Context, server LDAPServer) error {
+	if err := validateLDAPServer(server); err != nil {
+		return err
+	}
+	before, err := c.GetLDAPServer(ctx, server.Name)
+	if err != nil {
+		return err
+	}
+	server.ID, server.Order = before.ID, before.Order
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceLDAPServer, Name: server.Name, Action: ActionUpdate, Before: before, After: withoutLDAPPassword(server)})
+	} else {
+		notFound := newError(ErrLDAPServerNotFound, ldapServerNotFoundInfo, server.Name)
+		if err := c.doSecurity(ctx, "PUT", fmt.Sprintf("%s/%s", ldapPath, url.PathEscape(server.Name)), server, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(ldapServerUpdatedInfo, server.Name)
+	return nil
+}
+
+// DeleteLDAPServer deletes a LDAP server
+func (c *Client) DeleteLDAPServer(ctx context.Context, name string) error {
+	before, err := c.GetLDAPServer(ctx, name)
+	if err != nil {
+		return err
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceLDAPServer, Name: name, Action: ActionDelete, Before: before})
+	} else {
+		notFound := newError(ErrLDAPServerNotFound, ldapServerNotFoundInfo, name)
+		if err := c.doSecurity(ctx, "DELETE", fmt.Sprintf("%s/%s", ldapPath, url.PathEscape(name)), nil, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(ldapServerDeletedInfo, name)
+	return nil
+}
+
+// SetLDAPServerOrder changes the order in which the LDAP servers are queried. Every server must be listed
+func (c *Client) SetLDAPServerOrder(ctx context.Context, names ...string) error {
+	servers, err := c.getLDAPServers(ctx)
+	if err != nil {
+		return err
+	}
+	var current []string
+	for _, s := range servers {
+		current = append(current, s.Name)
+	}
+	if err := validateLDAPServerOrder(current, names); err != nil {
+		return err
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceLDAPServer, Name: strings.Join(names, ","), Action: ActionUpdate, Before: current, After: names})
+	} else if err := c.doSecurity(ctx, "POST", fmt.Sprintf("%s/change-order", ldapPath), names, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(ldapOrderChangedInfo, names)
+	return nil
+}
+
+// validateLDAPServerOrder checks that the order lists every configured LDAP server exactly once
+func validateLDAPServerOrder(current, names []string) error {
+	for i, name := range names {
+		if !entryExists(current, name) {
+			return newError(ErrLDAPServerNotFound, ldapServerNotFoundInfo, name)
+		}
+		if entryExists(names[:i], name) {
+			return newError(ErrInvalidInput, ldapOrderDuplicateInfo, name)
+		}
+	}
+	for _, name := range current {
+		if !entryExists(names, name) {
+			return newError(ErrInvalidInput, ldapOrderIncompleteInfo, name)
+		}
+	}
+	return nil
+}
+
+// VerifyLDAPConnection checks that nexus can connect to a LDAP server and authenticate with its credentials.
+// The server does not have to exist in nexus. It uses the extdirect API of the nexus UI as the REST API does not
+// provide the verification
+func (c *Client) VerifyLDAPConnection(ctx context.Context, server LDAPServer) error {
+	if err := validateLDAPServer(server); err != nil {
+		return err
+	}
+	return c.doExtDirect(ctx, "ldap_LdapServer", "verifyConnection", toLDAPServerXO(server), nil)
+}
+
+// VerifyLDAPUserMapping returns the users and their groups found using the user and group mapping of a LDAP server.
+// The server does not have to exist in nexus
+func (c *Client) VerifyLDAPUserMapping(ctx context.Context, server LDAPServer) ([]LDAPUser, error) {
+	if err := validateLDAPServer(server); err != nil {
+		return nil, err
+	}
+	var users []LDAPUser
+	if err := c.doExtDirect(ctx, "ldap_LdapServer", "verifyUserMapping", toLDAPServerXO(server), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CreateExternalRole creates a role mapping a group of an external source eg: RoleSourceLDAP to nexus roles and
+// privileges. The id of the role is the name of the group
+func (c *Client) CreateExternalRole(ctx context.Context, source, group, description, roleMembers, rolePrivileges string) error {
+	if source == "" || source == defaultRoleSource {
+		return newError(ErrInvalidInput, externalRoleSourceInfo, source)
+	}
+	return c.createRole(ctx, group, source, description, roleMembers, rolePrivileges)
+}
+
+func (c *Client) getLDAPServers(ctx context.Context) ([]LDAPServer, error) {
+	var servers []LDAPServer
+	if err := c.doSecurity(ctx, "GET", ldapPath, nil, &servers, nil); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(servers, func(i, j int) bool { return servers[i].Order < servers[j].Order })
+	return servers, nil
+}
+
+func (c *Client) ldapServerExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.GetLDAPServer(ctx, name)
+	if errors.Is(err, ErrLDAPServerNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// doExtDirect calls a method of the extdirect API and unmarshals the data of the result in out when out is not nil
+func (c *Client) doExtDirect(ctx context.Context, action, method string, data, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{"action": action, "method": method, "data": []interface{}{data}, "type": "rpc", "tid": 1})
+	if err != nil {
+		return fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	url := fmt.Sprintf("%s/%s", c.baseURL, extDirectPath)
+	respBody, status, err := c.doRequest(ctx, "POST", url, RequestBody{Json: payload})
+	if err != nil {
+		return err
+	}
+	if !isSuccessStatus(status) {
+		return newAPIError("POST", url, status, respBody)
+	}
+	var resp extDirectResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	if resp.Type == "exception" {
+		return newError(ErrInvalidInput, extDirectFailedInfo, action, method, resp.Message)
+	}
+	if !resp.Result.Success {
+		return newError(ErrInvalidInput, extDirectFailedInfo, action, method, resp.Result.Message)
+	}
+	if out != nil && len(resp.Result.Data) > 0 {
+		if err := json.Unmarshal(resp.Result.Data, out); err != nil {
+			return fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+		}
+	}
+	return nil
+}
+
+// toLDAPServerXO converts a LDAP server to the object of the extdirect API, whose fields differ from the REST API
+func toLDAPServerXO(server LDAPServer) map[string]interface{} {
+	var xo map[string]interface{}
+	// a LDAP server always marshals to a json object
+	_ = convert(server, &xo)
+	xo["connectionTimeout"] = server.ConnectionTimeoutSeconds
+	xo["connectionRetryDelay"] = server.ConnectionRetryDelaySeconds
+	delete(xo, "connectionTimeoutSeconds")
+	delete(xo, "connectionRetryDelaySeconds")
+	xo["authScheme"] = ldapAuthSchemesXO[server.AuthScheme]
+	return xo
+}
+
+// withoutLDAPPassword removes the password of a server which is recorded in a plan
+func withoutLDAPPassword(server LDAPServer) LDAPServer {
+	server.AuthPassword = ""
+	return server
+}
+
+func validateLDAPServer(server LDAPServer) error {
+	if server.Name == "" || server.Host == "" || server.SearchBase == "" {
+		return newError(ErrInvalidInput, ldapServerRequiredInfo)
+	}
+	if !entryExists(LDAPProtocols, server.Protocol) {
+		return newError(ErrInvalidInput, ldapProtocolNotValidInfo, server.Protocol, LDAPProtocols)
+	}
+	if server.Port < 1 || server.Port > 65535 {
+		return newError(ErrInvalidInput, ldapPortNotValidInfo, server.Port)
+	}
+	if _, ok := ldapAuthSchemesXO[server.AuthScheme]; !ok {
+		return newError(ErrInvalidInput, ldapAuthSchemeNotValidInfo, server.AuthScheme, LDAPAuthSchemes)
+	}
+	if server.AuthScheme != "NONE" && (server.AuthUsername == "" || server.AuthPassword == "") {
+		return newError(ErrInvalidInput, ldapCredentialsRequiredInfo, server.AuthScheme)
+	}
+	if server.UserObjectClass == "" || server.UserIDAttribute == "" || server.UserRealNameAttribute == "" || server.UserEmailAddressAttribute == "" {
+		return newError(ErrInvalidInput, ldapUserMappingRequiredInfo)
+	}
+	if !server.LDAPGroupsAsRoles {
+		return nil
+	}
+	switch server.GroupType {
+	case LDAPGroupsStatic:
+		if server.GroupObjectClass == "" || server.GroupIDAttribute == "" || server.GroupMemberAttribute == "" || server.GroupMemberFormat == "" {
+			return newError(ErrInvalidInput, ldapStaticGroupRequiredInfo)
+		}
+	case LDAPGroupsDynamic:
+		if server.UserMemberOfAttribute == "" {
+			return newError(ErrInvalidInput, ldapDynamicGroupRequiredInfo)
+		}
+	default:
+		return newError(ErrInvalidInput, ldapGroupTypeNotValidInfo, server.GroupType, LDAPGroupTypes)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package nxrm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateLDAPServerOrder(t *testing.T) {
+	current := []string{"primary", "secondary"}
+	tests := []struct {
+		name    string
+		names   []string
+		wantErr error
+	}{
+		{"every server", []string{"secondary", "primary"}, nil},
+		{"unknown server", []string{"primary", "secondary", "other"}, ErrLDAPServerNotFound},
+		{"duplicate server", []string{"primary", "secondary", "primary"}, ErrInvalidInput},
+		{"missing server", []string{"primary"}, ErrInvalidInput},
+		{"no servers", nil, ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateLDAPServerOrder(current, tt.names); !errors.Is(err, tt.wantErr) {
+				t.Errorf("validateLDAPServerOrder() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package nxrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+const ResourceScript = "script"
+
+// Plan records the changes of the mutating operations of a client created with WithDryRun.
+// A Plan can be shared by several goroutines
+type Plan struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+// Changes returns the recorded changes in the order in which they were planned
+func (p *Plan) Changes() []Change {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Change(nil), p.changes...)
+}
+
+// Reset removes the recorded changes
+func (p *Plan) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = nil
+}
+
+// MarshalJSON marshals the recorded changes
+func (p *Plan) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.Changes())
+}
+
+func (p *Plan) add(change Change) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.changes = append(p.changes, change)
+}
+
+// dryRunLogger marks the messages logged by a client in dry run mode
+type dryRunLogger struct {
+	Logger
+}
+
+func (l dryRunLogger) Printf(format string, v ...interface{}) {
+	l.Logger.Printf("[dry run] "+format, v...)
+}
+
+// planBackend runs the lookups against nexus but records the changes in a plan instead of making them
+type planBackend struct {
+	backend
+	plan *Plan
+}
+
+func (b *planBackend) createRepository(ctx context.Context, repository Repository) error {
+	_, err := b.backend.getRepository(ctx, repository.Name)
+	if err == nil {
+		return newError(ErrRepositoryExists, repoExistsInfo, repository.Name)
+	} else if !errors.Is(err, ErrRepositoryNotFound) {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceRepository, Name: repository.Name, Action: ActionCreate, After: repository})
+	return nil
+}
+
+func (b *planBackend) updateGroupMembers(ctx context.Context, repository Repository) error {
+	before, err := b.backend.getRepository(ctx, repository.Name)
+	if err != nil {
+		return err
+	}
+	after := before
+	after.Attributes.Group = repository.Attributes.Group
+	b.plan.add(Change{Resource: ResourceRepository, Name: repository.Name, Action: ActionUpdate, Before: before, After: after})
+	return nil
+}
+
+func (b *planBackend) updateRepository(ctx context.Context, repository Repository) error {
+	before, err := b.backend.getRepository(ctx, repository.Name)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceRepository, Name: repository.Name, Action: ActionUpdate, Before: before, After: repository})
+	return nil
+}
+
+func (b *planBackend) deleteRepository(ctx context.Context, name string) error {
+	before, err := b.backend.getRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceRepository, Name: name, Action: ActionDelete, Before: before})
+	return nil
+}
+
+func (b *planBackend) createSelector(ctx context.Context, selector ContentSelector) error {
+	b.plan.add(Change{Resource: ResourceSelector, Name: selector.Name, Action: ActionCreate, After: selector})
+	return nil
+}
+
+func (b *planBackend) updateSelector(ctx context.Context, selector ContentSelector) error {
+	before, err := b.getSelector(ctx, selector.Name)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceSelector, Name: selector.Name, Action: ActionUpdate, Before: before, After: selector})
+	return nil
+}
+
+func (b *planBackend) deleteSelector(ctx context.Context, selector ContentSelector) error {
+	before, err := b.getSelector(ctx, selector.Name)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceSelector, Name: selector.Name, Action: ActionDelete, Before: before})
+	return nil
+}
+
+func (b *planBackend) getSelector(ctx context.Context, name string) (ContentSelector, error) {
+	selectors, err := b.backend.getSelectors(ctx)
+	if err != nil {
+		return ContentSelector{}, err
+	}
+	for _, cs := range selectors {
+		if cs.Name == name {
+			return cs, nil
+		}
+	}
+	return ContentSelector{}, newError(ErrSelectorNotFound, selectorNotFoundInfo, name)
+}
+
+func (b *planBackend) createPrivilege(ctx context.Context, privilege Privilege) error {
+	b.plan.add(Change{Resource: ResourcePrivilege, Name: privilege.Name, Action: ActionCreate, After: privilege})
+	return nil
+}
+
+func (b *planBackend) updatePrivilege(ctx context.Context, privilege Privilege) error {
+	before, err := b.getPrivilege(ctx, privilege.Name)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourcePrivilege, Name: privilege.Name, Action: ActionUpdate, Before: before, After: privilege})
+	return nil
+}
+
+func (b *planBackend) deletePrivilege(ctx context.Context, privilege Privilege) error {
+	before, err := b.getPrivilege(ctx, privilege.Name)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourcePrivilege, Name: privilege.Name, Action: ActionDelete, Before: before})
+	return nil
+}
+
+func (b *planBackend) getPrivilege(ctx context.Context, name string) (Privilege, error) {
+	privileges, err := b.backend.getPrivileges(ctx)
+	if err != nil {
+		return Privilege{}, err
+	}
+	for _, p := range privileges {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Privilege{}, newError(ErrPrivilegeNotFound, privilegeNotFoundInfo, name)
+}
+
+func (b *planBackend) createRole(ctx context.Context, role Role) error {
+	b.plan.add(Change{Resource: ResourceRole, Name: role.RoleID, Action: ActionCreate, After: role})
+	return nil
+}
+
+func (b *planBackend) updateRole(ctx context.Context, role Role) error {
+	before, err := b.getRole(ctx, role.RoleID)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceRole, Name: role.RoleID, Action: ActionUpdate, Before: before, After: role})
+	return nil
+}
+
+func (b *planBackend) deleteRole(ctx context.Context, id string) error {
+	before, err := b.getRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	b.plan.add(Change{Resource: ResourceRole, Name: id, Action: ActionDelete, Before: before})
+	return nil
+}
+
+func (b *planBackend) getRole(ctx context.Context, id string) (Role, error) {
+	roles, err := b.backend.getRoles(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+	for _, r := range roles {
+		if r.RoleID == id {
+			return r, nil
+		}
+	}
+	return Role{}, newError(ErrRoleNotFound, roleNotFoundInfo, id)
+}
@@ -1,10 +1,18 @@
 package nxrm
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
-	"os"
+	"strings"
+)
+
+const (
+	PrivilegeWildcard                  = "wildcard"
+	PrivilegeApplication               = "application"
+	PrivilegeRepositoryView            = "repository-view"
+	PrivilegeRepositoryAdmin           = "repository-admin"
+	PrivilegeRepositoryContentSelector = "repository-content-selector"
+	PrivilegeScript                    = "script"
 )
 
 type Privilege struct {
@@ -16,137 +24,308 @@ type Privilege struct {
 	ReadOnly    bool                `json:"readOnly"`
 }
 
+// PrivilegeProperties are the properties of the privileges, the properties used depend on the type of the privilege:
+//   - wildcard: Pattern
+//   - application: Domain and Actions
+//   - repository-view and repository-admin: Format, Repository and Actions
+//   - repository-content-selector: ContentSelector, Repository and Actions
+//   - script: ScriptName and Actions
 type PrivilegeProperties struct {
 	ContentSelector string `json:"contentSelector"`
 	Repository      string `json:"repository"`
 	Actions         string `json:"actions"`
+	// Format is the format of the repositories eg: maven2 or * for every format
+	Format string `json:"format,omitempty"`
+	// Pattern is a permission pattern eg: nexus:repository-view:maven2:*:read
+	Pattern string `json:"pattern,omitempty"`
+	// Domain is a part of the application eg: users, roles or settings
+	Domain     string `json:"domain,omitempty"`
+	ScriptName string `json:"name,omitempty"`
 }
 
-func ListPrivileges(name string) {
+// ListPrivileges prints the details of a privilege when a name is provided,
+// otherwise prints the names of all the privileges
+func (c *Client) ListPrivileges(ctx context.Context, name string) error {
 	if name != "" {
-		privilege := getPrivilege(name)
+		privilege, err := c.GetPrivilege(ctx, name)
+		if err != nil {
+			return err
+		}
 		fmt.Printf("%+v\n", privilege)
-	} else {
-		pNames := getPrivilegeNames()
-		printStringSlice(pNames)
-		fmt.Printf("Number of privileges in nexus : %d\n", len(pNames))
+		return nil
+	}
+	privileges, err := c.GetPrivileges(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, p := range privileges {
+		fmt.Println(p.Name)
 	}
+	fmt.Printf("Number of privileges in nexus : %d\n", len(privileges))
+	return nil
 }
 
-func CreatePrivilege(name, description, selectorName, repoName, action string) {
-	if name == "" || selectorName == "" || repoName == "" {
-		log.Printf("%s : %s", getfuncName(), createPrivilegeRequiredInfo)
-		os.Exit(1)
+// GetPrivileges returns the privileges matching the options
+func (c *Client) GetPrivileges(ctx context.Context, opts ListOptions) ([]Privilege, error) {
+	if err := opts.validate(ResourcePrivilege); err != nil {
+		return nil, err
+	}
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if !privilegeExists(name) {
-		properties := PrivilegeProperties{ContentSelector: validateSelectorForPriv(selectorName), Repository: validateRepoForPriv(repoName), Actions: getPrivilegeActions(action)}
-		payload, err := json.Marshal(Privilege{ID: toLower(name), Name: toLower(name), Description: getPrivilegeDescription(description), Type: getPrivilegeType(), Properties: properties, ReadOnly: false})
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript(createPrivilegeScript, string(payload))
-		if result.Status == successStatus {
-			log.Printf(createPrivilegeSuccessInfo, name)
+	var result []Privilege
+	for _, p := range privileges {
+		if opts.match(p.Name, p.Type, "") {
+			result = append(result, p)
 		}
-	} else {
-		log.Printf(privilegeExistsInfo, name)
 	}
+	return result, nil
+}
+
+// GetPrivilege returns a privilege
+func (c *Client) GetPrivilege(ctx context.Context, name string) (Privilege, error) {
+	return c.getPrivilege(ctx, name)
+}
+
+// NewWildcardPrivilege returns a privilege granting the permissions matching a pattern
+func NewWildcardPrivilege(name, pattern string) Privilege {
+	return newPrivilege(name, PrivilegeWildcard, PrivilegeProperties{Pattern: pattern})
+}
+
+// NewApplicationPrivilege returns a privilege granting actions eg: read on a domain of the application
+func NewApplicationPrivilege(name, domain string, actions ...string) Privilege {
+	return newPrivilege(name, PrivilegeApplication, PrivilegeProperties{Domain: domain, Actions: strings.Join(actions, ",")})
+}
+
+// NewRepositoryViewPrivilege returns a privilege granting actions on the content of a repository. The repository is
+// either a repository name or * for every repository of the format
+func NewRepositoryViewPrivilege(name, format, repository string, actions ...string) Privilege {
+	properties := PrivilegeProperties{Format: format, Repository: repository, Actions: strings.Join(actions, ",")}
+	return newPrivilege(name, PrivilegeRepositoryView, properties)
+}
+
+// NewRepositoryAdminPrivilege returns a privilege granting actions on the configuration of a repository
+func NewRepositoryAdminPrivilege(name, format, repository string, actions ...string) Privilege {
+	properties := PrivilegeProperties{Format: format, Repository: repository, Actions: strings.Join(actions, ",")}
+	return newPrivilege(name, PrivilegeRepositoryAdmin, properties)
+}
+
+// NewContentSelectorPrivilege returns a privilege granting actions on the content of a repository matching a content
+// selector. The repository is either a repository name, * for every repository or *-<format> eg: *-maven2
+func NewContentSelectorPrivilege(name, selector, repository string, actions ...string) Privilege {
+	properties := PrivilegeProperties{ContentSelector: selector, Repository: repository, Actions: strings.Join(actions, ",")}
+	return newPrivilege(name, PrivilegeRepositoryContentSelector, properties)
+}
+
+// NewScriptPrivilege returns a privilege granting actions eg: run on a script
+func NewScriptPrivilege(name, script string, actions ...string) Privilege {
+	return newPrivilege(name, PrivilegeScript, PrivilegeProperties{ScriptName: script, Actions: strings.Join(actions, ",")})
+}
+
+// CreateTypedPrivilege creates a privilege of any type, see NewWildcardPrivilege, NewApplicationPrivilege,
+// NewRepositoryViewPrivilege, NewRepositoryAdminPrivilege, NewContentSelectorPrivilege and NewScriptPrivilege
+func (c *Client) CreateTypedPrivilege(ctx context.Context, privilege Privilege) error {
+	privilege, err := validatePrivilege(privilege)
+	if err != nil {
+		return err
+	}
+	exists, err := c.privilegeExists(ctx, privilege.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrPrivilegeExists, privilegeExistsInfo, privilege.Name)
+	}
+	if err := c.validatePrivilegeReferences(ctx, privilege); err != nil {
+		return err
+	}
+	privilege.ID = privilege.Name
+	privilege.Description = getPrivilegeDescription(privilege.Description)
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.createPrivilege(ctx, privilege); err != nil {
+		return err
+	}
+	c.logger.Printf(createPrivilegeSuccessInfo, privilege.Name)
+	return nil
+}
+
+// ReplacePrivilege replaces the description and the properties of a privilege. The type cannot be changed
+func (c *Client) ReplacePrivilege(ctx context.Context, privilege Privilege) error {
+	privilege, err := validatePrivilege(privilege)
+	if err != nil {
+		return err
+	}
+	current, err := c.getPrivilege(ctx, privilege.Name)
+	if err != nil {
+		return err
+	}
+	if current.Type != privilege.Type {
+		return newError(ErrInvalidInput, privilegeTypeChangedInfo, privilege.Name, current.Type, privilege.Type)
+	}
+	if err := c.validatePrivilegeReferences(ctx, privilege); err != nil {
+		return err
+	}
+	privilege.ID = current.ID
+	privilege.Description = getPrivilegeDescription(privilege.Description)
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.updatePrivilege(ctx, privilege); err != nil {
+		return err
+	}
+	c.logger.Printf(updatePrivilegeSuccessInfo, privilege.Name)
+	return nil
+}
+
+func (c *Client) CreatePrivilege(ctx context.Context, name, description, selectorName, repoName, action string) error {
+	if name == "" || selectorName == "" || repoName == "" {
+		return newError(ErrInvalidInput, createPrivilegeRequiredInfo)
+	}
+	exists, err := c.privilegeExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrPrivilegeExists, privilegeExistsInfo, name)
+	}
+	if err := c.validateSelectorForPriv(ctx, selectorName); err != nil {
+		return err
+	}
+	if err := c.validateRepoForPriv(ctx, repoName); err != nil {
+		return err
+	}
+	properties := PrivilegeProperties{ContentSelector: selectorName, Repository: repoName, Actions: getPrivilegeActions(action)}
+	privilege := Privilege{ID: toLower(name), Name: toLower(name), Description: getPrivilegeDescription(description), Type: getPrivilegeType(), Properties: properties, ReadOnly: false}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.createPrivilege(ctx, privilege); err != nil {
+		return err
+	}
+	c.logger.Printf(createPrivilegeSuccessInfo, name)
+	return nil
 }
 
-func UpdatePrivilege(name, description, selectorName, repoName, action string) {
+func (c *Client) UpdatePrivilege(ctx context.Context, name, description, selectorName, repoName, action string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	privilege, err := c.getPrivilege(ctx, name)
+	if err != nil {
+		return err
 	}
-	privilege := getPrivilege(name)
 	if description != "" {
 		privilege.Description = description
 	}
 	if selectorName != "" {
-		privilege.Properties.ContentSelector = validateSelectorForPriv(selectorName)
+		if err := c.validateSelectorForPriv(ctx, selectorName); err != nil {
+			return err
+		}
+		privilege.Properties.ContentSelector = selectorName
 	}
 	if repoName != "" {
-		privilege.Properties.Repository = validateRepoForPriv(repoName)
+		if err := c.validateRepoForPriv(ctx, repoName); err != nil {
+			return err
+		}
+		privilege.Properties.Repository = repoName
 	}
 	if action != "" {
 		privilege.Properties.Actions = getPrivilegeActions(action)
 	}
-	if privilegeExists(name) {
-		payload, err := json.Marshal(privilege)
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript(updatePrivilegeScript, string(payload))
-		if result.Status == successStatus {
-			log.Printf(updatePrivilegeSuccessInfo, name)
-		}
-	} else {
-		log.Printf(privilegeNotFoundInfo, name)
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
 	}
+	if err := b.updatePrivilege(ctx, privilege); err != nil {
+		return err
+	}
+	c.logger.Printf(updatePrivilegeSuccessInfo, name)
+	return nil
 }
 
-func DeletePrivilege(name string) {
+func (c *Client) DeletePrivilege(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	}
-	if privilegeExists(name) {
-		payload, err := json.Marshal(Privilege{ID: getPrivilegeID(name)})
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript(deletePrivilegeScript, string(payload))
-		if result.Status == successStatus {
-			log.Printf(deletePrivilegeSuccessInfo, name)
-		}
-	} else {
-		log.Printf(privilegeNotFoundInfo, name)
+	privilege, err := c.getPrivilege(ctx, name)
+	if err != nil {
+		return err
 	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.deletePrivilege(ctx, privilege); err != nil {
+		return err
+	}
+	c.logger.Printf(deletePrivilegeSuccessInfo, name)
+	return nil
 }
 
-func getPrivileges() []Privilege {
-	payload, err := json.Marshal(Privilege{})
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(getPrivilegesScript, string(payload))
-	return result.Privileges
+func (c *Client) getPrivileges(ctx context.Context) ([]Privilege, error) {
+	b, err := c.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.getPrivileges(ctx)
 }
 
-func getPrivilege(name string) Privilege {
+func (c *Client) getPrivilege(ctx context.Context, name string) (Privilege, error) {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return Privilege{}, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return Privilege{}, err
 	}
-	var privilege Privilege
-	privileges := getPrivileges()
 	for _, p := range privileges {
 		if p.Name == name {
-			privilege = p
+			return p, nil
 		}
 	}
-	if privilege.Name == "" {
-		log.Printf(privilegeNotFoundInfo, name)
-		os.Exit(1)
-	}
-	return privilege
+	return Privilege{}, newError(ErrPrivilegeNotFound, privilegeNotFoundInfo, name)
 }
 
-func getPrivilegeNames() []string {
+func (c *Client) getPrivilegeNames(ctx context.Context) ([]string, error) {
 	var pNames []string
-	privileges := getPrivileges()
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return nil, err
+	}
 	for _, p := range privileges {
 		pNames = append(pNames, p.Name)
 	}
-	return pNames
+	return pNames, nil
 }
 
-func getPrivilegeID(name string) string {
-	return getPrivilege(name).ID
+func (c *Client) getPrivilegeID(ctx context.Context, name string) (string, error) {
+	privilege, err := c.getPrivilege(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return privilege.ID, nil
 }
 
-func privilegeExists(name string) bool {
-	pNames := getPrivilegeNames()
-	if entryExists(pNames, name) {
-		return true
+func (c *Client) privilegeExists(ctx context.Context, name string) (bool, error) {
+	pNames, err := c.getPrivilegeNames(ctx)
+	if err != nil {
+		return false, err
 	}
-	return false
+	return entryExists(pNames, name), nil
 }
 
 func getPrivilegeType() string {
-	return "repository-content-selector"
+	return PrivilegeRepositoryContentSelector
+}
+
+func newPrivilege(name, privilegeType string, properties PrivilegeProperties) Privilege {
+	return Privilege{ID: name, Name: name, Type: privilegeType, Properties: properties}
 }
 
 func getPrivilegeDescription(description string) string {
@@ -166,23 +345,119 @@ func getPrivilegeActions(action string) string {
 	}
 }
 
-func validateSelectorForPriv(selectorName string) string {
-	if !selectorExists(selectorName) {
-		log.Printf("%s : "+selectorNotFoundInfo, getfuncName(), selectorName)
-		os.Exit(1)
+func (c *Client) validateSelectorForPriv(ctx context.Context, selectorName string) error {
+	exists, err := c.selectorExists(ctx, selectorName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrSelectorNotFound, selectorNotFoundInfo, selectorName)
 	}
-	return selectorName
+	return nil
 }
 
-func validateRepoForPriv(repoName string) string {
-	var allowedFormats []string
-	allowedFormats = append(allowedFormats, "*")
+func (c *Client) validateRepoForPriv(ctx context.Context, repoName string) error {
+	if isRepositoryWildcard(repoName) {
+		return nil
+	}
+	exists, err := c.repositoryExists(ctx, repoName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrRepositoryNotFound, repositoryNotFoundInfo, repoName)
+	}
+	return nil
+}
+
+// isRepositoryWildcard checks if the repository of a privilege matches all the repositories eg: * or of a format eg: *-maven2
+func isRepositoryWildcard(repoName string) bool {
+	allowedFormats := []string{"*"}
 	for _, format := range RepoFormats {
-		allowedFormats = append(allowedFormats, fmt.Sprintf("*-%s", validateRepositoryFormat(format)))
+		format, _ = validateRepositoryFormat(format)
+		allowedFormats = append(allowedFormats, fmt.Sprintf("*-%s", format))
+	}
+	return entryExists(allowedFormats, repoName)
+}
+
+// validatePrivilegeReferences checks that the content selector and the repository of a privilege exist, and that the
+// format of a repository-view or repository-admin privilege on a repository is the format of the repository
+func (c *Client) validatePrivilegeReferences(ctx context.Context, privilege Privilege) error {
+	switch privilege.Type {
+	case PrivilegeRepositoryContentSelector:
+		if err := c.validateSelectorForPriv(ctx, privilege.Properties.ContentSelector); err != nil {
+			return err
+		}
+		return c.validateRepoForPriv(ctx, privilege.Properties.Repository)
+	case PrivilegeRepositoryView, PrivilegeRepositoryAdmin:
+		if privilege.Properties.Repository == "*" {
+			return nil
+		}
+		repository, err := c.getRepository(ctx, privilege.Properties.Repository)
+		if err != nil {
+			return err
+		}
+		if format := privilege.Properties.Format; format != "*" && format != repository.Format {
+			return newError(ErrInvalidInput, privilegeFormatInfo, privilege.Name, format, repository.Name, repository.Format)
+		}
+	}
+	return nil
+}
+
+// validatePrivilege validates the properties of a privilege according to its type and returns the privilege with
+// its format normalised. The type defaults to repository-content-selector
+func validatePrivilege(privilege Privilege) (Privilege, error) {
+	if privilege.Name == "" {
+		return privilege, newError(ErrInvalidInput, nameRequiredInfo)
 	}
-	if !entryExists(allowedFormats, repoName) && !repositoryExists(repoName) {
-		log.Printf("%s : "+repositoryNotFoundInfo, getfuncName(), repoName)
-		os.Exit(1)
+	if privilege.Type == "" {
+		privilege.Type = getPrivilegeType()
+	}
+	actions, ok := privilegeTypeActions[privilege.Type]
+	if !ok {
+		return privilege, newError(ErrInvalidInput, privilegeTypeNotValidInfo, privilege.Type, PrivilegeTypes)
+	}
+	props := privilege.Properties
+	switch privilege.Type {
+	case PrivilegeWildcard:
+		if props.Pattern == "" {
+			return privilege, newError(ErrInvalidInput, privilegePropertiesRequiredInfo, privilege.Type, "pattern")
+		}
+		return privilege, nil
+	case PrivilegeApplication:
+		if props.Domain == "" {
+			return privilege, newError(ErrInvalidInput, privilegePropertiesRequiredInfo, privilege.Type, "domain")
+		}
+	case PrivilegeRepositoryView, PrivilegeRepositoryAdmin:
+		if props.Format == "" || props.Repository == "" {
+			return privilege, newError(ErrInvalidInput, privilegePropertiesRequiredInfo, privilege.Type, "format and repository")
+		}
+		if strings.HasPrefix(props.Repository, "*-") {
+			return privilege, newError(ErrInvalidInput, privilegeRepositoryNotValidInfo, props.Repository, privilege.Type)
+		}
+		if props.Format != "*" {
+			format, err := validateRepositoryFormat(props.Format)
+			if err != nil {
+				return privilege, err
+			}
+			privilege.Properties.Format = format
+		}
+	case PrivilegeRepositoryContentSelector:
+		if props.ContentSelector == "" || props.Repository == "" {
+			return privilege, newError(ErrInvalidInput, privilegePropertiesRequiredInfo, privilege.Type, "content selector and repository")
+		}
+	case PrivilegeScript:
+		if props.ScriptName == "" {
+			return privilege, newError(ErrInvalidInput, privilegePropertiesRequiredInfo, privilege.Type, "script name")
+		}
+	}
+	if props.Actions == "" {
+		return privilege, newError(ErrInvalidInput, privilegePropertiesRequiredInfo, privilege.Type, "actions")
+	}
+	for _, action := range strings.Split(props.Actions, ",") {
+		if !entryExists(actions, strings.TrimSpace(action)) {
+			return privilege, newError(ErrInvalidInput, privilegeActionNotValidInfo, action, privilege.Type, actions)
+		}
 	}
-	return repoName
+	return privilege, nil
 }
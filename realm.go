@@ -0,0 +1,187 @@
+package nxrm
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	RealmLocalAuthenticating = "NexusAuthenticatingRealm"
+	RealmLocalAuthorizing    = "NexusAuthorizingRealm"
+	RealmLDAP                = "LdapRealm"
+	RealmDockerToken         = "DockerToken"
+	RealmNpmToken            = "NpmToken"
+	RealmNuGetAPIKey         = "NuGetApiKey"
+	RealmConanToken          = "org.sonatype.repository.conan.internal.security.token.ConanTokenRealm"
+	RealmDefaultRole         = "DefaultRole"
+	RealmRemoteUserToken     = "rutauth-realm"
+
+	ResourceRealm = "realm"
+)
+
+// Realm is a security realm, the active realms are used by order to authenticate and authorize the requests
+type Realm struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// formatRealms lists the realm required by the repositories of a format eg: the docker clients pull anonymously
+// using a bearer token when the docker repositories do not force the basic authentication
+var formatRealms = map[string]string{
+	"docker": RealmDockerToken,
+	"npm":    RealmNpmToken,
+	"nuget":  RealmNuGetAPIKey,
+	"conan":  RealmConanToken,
+}
+
+// ListRealms prints the available realms, the active realms are printed first by order
+func (c *Client) ListRealms(ctx context.Context) error {
+	available, err := c.GetAvailableRealms(ctx)
+	if err != nil {
+		return err
+	}
+	active, err := c.GetActiveRealms(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range active {
+		fmt.Printf("%s (active)\n", id)
+	}
+	for _, r := range available {
+		if !entryExists(active, r.ID) {
+			fmt.Println(r.ID)
+		}
+	}
+	fmt.Printf("Number of active realms : %d of %d\n", len(active), len(available))
+	return nil
+}
+
+// GetAvailableRealms returns the realms which can be activated
+func (c *Client) GetAvailableRealms(ctx context.Context) ([]Realm, error) {
+	var realms []Realm
+	if err := c.doSecurity(ctx, "GET", fmt.Sprintf("%s/available", realmsPath), nil, &realms, nil); err != nil {
+		return nil, err
+	}
+	return realms, nil
+}
+
+// GetActiveRealms returns the ids of the active realms by order
+func (c *Client) GetActiveRealms(ctx context.Context) ([]string, error) {
+	var ids []string
+	if err := c.doSecurity(ctx, "GET", fmt.Sprintf("%s/active", realmsPath), nil, &ids, nil); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetActiveRealms replaces the active realms, the realms are used in the order of ids
+func (c *Client) SetActiveRealms(ctx context.Context, ids ...string) error {
+	before, err := c.GetActiveRealms(ctx)
+	if err != nil {
+		return err
+	}
+	return c.setActiveRealms(ctx, before, ids)
+}
+
+// AppendRealms activates realms after the active realms. The realms which are already active are skipped
+func (c *Client) AppendRealms(ctx context.Context, ids ...string) error {
+	if len(ids) < 1 {
+		return newError(ErrInvalidInput, realmsRequiredInfo)
+	}
+	before, err := c.GetActiveRealms(ctx)
+	if err != nil {
+		return err
+	}
+	after := append([]string(nil), before...)
+	for _, id := range ids {
+		if entryExists(after, id) {
+			c.logger.Printf(realmAlreadyActiveInfo, id)
+			continue
+		}
+		after = append(after, id)
+	}
+	if len(after) == len(before) {
+		return nil
+	}
+	return c.setActiveRealms(ctx, before, after)
+}
+
+// RemoveRealms deactivates realms. The realms which are not active are skipped
+func (c *Client) RemoveRealms(ctx context.Context, ids ...string) error {
+	if len(ids) < 1 {
+		return newError(ErrInvalidInput, realmsRequiredInfo)
+	}
+	before, err := c.GetActiveRealms(ctx)
+	if err != nil {
+		return err
+	}
+	after := append([]string(nil), before...)
+	for _, id := range ids {
+		if !entryExists(after, id) {
+			c.logger.Printf(realmNotActiveInfo, id)
+			continue
+		}
+		after = removeEntryFromSlice(after, id)
+	}
+	if len(after) == len(before) {
+		return nil
+	}
+	return c.setActiveRealms(ctx, before, after)
+}
+
+// setActiveRealms replaces the active realms with a single request, so the realms are never partially changed
+func (c *Client) setActiveRealms(ctx context.Context, before, after []string) error {
+	if err := c.validateRealms(ctx, after); err != nil {
+		return err
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceRealm, Name: "active", Action: ActionUpdate, Before: before, After: after})
+	} else if err := c.doSecurity(ctx, "PUT", fmt.Sprintf("%s/active", realmsPath), after, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(realmsActivatedInfo, after)
+	return nil
+}
+
+// validateRealms checks that the realms are available and listed once
+func (c *Client) validateRealms(ctx context.Context, ids []string) error {
+	if len(ids) < 1 {
+		return newError(ErrInvalidInput, realmsRequiredInfo)
+	}
+	available, err := c.GetAvailableRealms(ctx)
+	if err != nil {
+		return err
+	}
+	var availableIDs []string
+	for _, r := range available {
+		availableIDs = append(availableIDs, r.ID)
+	}
+	for i, id := range ids {
+		if !entryExists(availableIDs, id) {
+			return newError(ErrInvalidInput, realmNotValidInfo, id, availableIDs)
+		}
+		if entryExists(ids[:i], id) {
+			return newError(ErrInvalidInput, realmDuplicateInfo, id)
+		}
+	}
+	return nil
+}
+
+// warnInactiveRealm logs a warning when the realm required by the format of a repository is not active, see
+// WithRealmWarnings. Docker repositories forcing the basic authentication do not require a realm
+func (c *Client) warnInactiveRealm(ctx context.Context, repository Repository) {
+	realm, ok := formatRealms[repository.Format]
+	if !ok || (repository.Format == "docker" && repository.Attributes.Docker.ForceBasicAuth) {
+		return
+	}
+	active, err := c.GetActiveRealms(ctx)
+	if err != nil {
+		if c.debug {
+			c.logger.Printf(realmCheckSkippedInfo, repository.Name, err)
+		}
+		return
+	}
+	if !entryExists(active, realm) {
+		c.logger.Printf(realmInactiveInfo, realm, repository.Format, repository.Name)
+	}
+}
@@ -0,0 +1,320 @@
+package nxrm
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+	OutputCSV   = "csv"
+)
+
+// Renderer writes a resource or a list of resources to w
+type Renderer interface {
+	Render(w io.Writer, v interface{}) error
+}
+
+// RendererFunc is an adapter to use an ordinary function as a Renderer
+type RendererFunc func(w io.Writer, v interface{}) error
+
+func (f RendererFunc) Render(w io.Writer, v interface{}) error {
+	return f(w, v)
+}
+
+// Tabular is implemented by values which can be rendered as a table or as csv.
+// Repositories, roles, privileges, content selectors, scripts and changes are supported out of the box
+type Tabular interface {
+	Header() []string
+	Rows() [][]string
+}
+
+var (
+	renderers = map[string]Renderer{
+		OutputJSON:  RendererFunc(renderJSON),
+		OutputYAML:  RendererFunc(renderYAML),
+		OutputTable: RendererFunc(renderTable),
+		OutputCSV:   RendererFunc(renderCSV),
+	}
+	outputFormats = []string{OutputJSON, OutputYAML, OutputTable, OutputCSV}
+)
+
+// RegisterRenderer adds or replaces the renderer of an output format
+func RegisterRenderer(format string, r Renderer) {
+	if _, ok := renderers[format]; !ok {
+		outputFormats = append(outputFormats, format)
+	}
+	renderers[format] = r
+}
+
+// NewRenderer returns the renderer of an output format
+func NewRenderer(format string) (Renderer, error) {
+	r, ok := renderers[format]
+	if !ok {
+		return nil, newError(ErrInvalidInput, outputFormatNotValidInfo, format, OutputFormats())
+	}
+	return r, nil
+}
+
+// OutputFormats returns the available output formats
+func OutputFormats() []string {
+	return append([]string(nil), outputFormats...)
+}
+
+// Render writes v to w in the output format
+func Render(w io.Writer, format string, v interface{}) error {
+	r, err := NewRenderer(format)
+	if err != nil {
+		return err
+	}
+	return r.Render(w, v)
+}
+
+func renderJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	return nil
+}
+
+// renderYAML converts v to json first so the field names of the json tags are used
+func renderYAML(w io.Writer, v interface{}) error {
+	data, err := toYAML(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func renderTable(w io.Writer, v interface{}) error {
+	t, err := tabular(v)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.Header(), "\t"))
+	for _, row := range t.Rows() {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+func renderCSV(w io.Writer, v interface{}) error {
+	t, err := tabular(v)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Header()); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows()); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// toYAML marshals v to yaml using the json field names
+func toYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	out, err := yaml.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %w", yamlMarshalError, err)
+	}
+	return out, nil
+}
+
+// table is a generic Tabular
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t table) Header() []string {
+	return t.header
+}
+
+func (t table) Rows() [][]string {
+	return t.rows
+}
+
+// tabular returns the table representation of the supported resources
+func tabular(v interface{}) (Tabular, error) {
+	switch r := v.(type) {
+	case Tabular:
+		return r, nil
+	case Repository:
+		return repositoriesTable([]Repository{r}), nil
+	case []Repository:
+		return repositoriesTable(r), nil
+	case Role:
+		return rolesTable([]Role{r}), nil
+	case []Role:
+		return rolesTable(r), nil
+	case Privilege:
+		return privilegesTable([]Privilege{r}), nil
+	case []Privilege:
+		return privilegesTable(r), nil
+	case ContentSelector:
+		return selectorsTable([]ContentSelector{r}), nil
+	case []ContentSelector:
+		return selectorsTable(r), nil
+	case Script:
+		return scriptsTable([]Script{r}), nil
+	case []Script:
+		return scriptsTable(r), nil
+	case BlobStore:
+		return blobStoresTable([]BlobStore{r}), nil
+	case []BlobStore:
+		return blobStoresTable(r), nil
+	case []BlobStoreUsage:
+		return blobStoreUsageTable(r), nil
+	case CleanupPolicy:
+		return cleanupPoliciesTable([]CleanupPolicy{r}), nil
+	case []CleanupPolicy:
+		return cleanupPoliciesTable(r), nil
+	case RoutingRule:
+		return routingRulesTable([]RoutingRule{r}), nil
+	case []RoutingRule:
+		return routingRulesTable(r), nil
+	case User:
+		return usersTable([]User{r}), nil
+	case []User:
+		return usersTable(r), nil
+	case []AnonymousRepository:
+		return anonymousRepositoriesTable(r), nil
+	case LDAPServer:
+		return ldapServersTable([]LDAPServer{r}), nil
+	case []LDAPServer:
+		return ldapServersTable(r), nil
+	case []Change:
+		return changesTable(r), nil
+	case *Plan:
+		return changesTable(r.Changes()), nil
+	}
+	return nil, newError(ErrInvalidInput, notTabularInfo, v)
+}
+
+func repositoriesTable(repositories []Repository) Tabular {
+	t := table{header: []string{"NAME", "FORMAT", "TYPE", "URL"}}
+	for _, r := range repositories {
+		t.rows = append(t.rows, []string{r.Name, r.Format, r.Type, r.URL})
+	}
+	return t
+}
+
+func rolesTable(roles []Role) Tabular {
+	t := table{header: []string{"ID", "NAME", "SOURCE", "ROLES", "PRIVILEGES"}}
+	for _, r := range roles {
+		t.rows = append(t.rows, []string{r.RoleID, r.Name, r.Source, strings.Join(r.Roles, ","), strings.Join(r.Privileges, ",")})
+	}
+	return t
+}
+
+func privilegesTable(privileges []Privilege) Tabular {
+	t := table{header: []string{"NAME", "TYPE", "CONTENT SELECTOR", "REPOSITORY", "ACTIONS"}}
+	for _, p := range privileges {
+		t.rows = append(t.rows, []string{p.Name, p.Type, p.Properties.ContentSelector, p.Properties.Repository, p.Properties.Actions})
+	}
+	return t
+}
+
+func selectorsTable(selectors []ContentSelector) Tabular {
+	t := table{header: []string{"NAME", "TYPE", "DESCRIPTION", "EXPRESSION"}}
+	for _, cs := range selectors {
+		t.rows = append(t.rows, []string{cs.Name, cs.Type, cs.Description, cs.Attributes.Expression})
+	}
+	return t
+}
+
+func scriptsTable(scripts []Script) Tabular {
+	t := table{header: []string{"NAME", "TYPE"}}
+	for _, s := range scripts {
+		t.rows = append(t.rows, []string{s.Name, s.Type})
+	}
+	return t
+}
+
+func blobStoresTable(blobStores []BlobStore) Tabular {
+	t := table{header: []string{"NAME", "TYPE", "BLOBS", "SIZE", "AVAILABLE"}}
+	for _, bs := range blobStores {
+		t.rows = append(t.rows, []string{bs.Name, bs.Type, strconv.FormatInt(bs.BlobCount, 10), strconv.FormatInt(bs.TotalSizeInBytes, 10), strconv.FormatInt(bs.AvailableSpaceInBytes, 10)})
+	}
+	return t
+}
+
+func blobStoreUsageTable(usage []BlobStoreUsage) Tabular {
+	t := table{header: []string{"BLOB STORE", "REPOSITORIES"}}
+	for _, u := range usage {
+		t.rows = append(t.rows, []string{u.BlobStore, strings.Join(u.Repositories, ",")})
+	}
+	return t
+}
+
+func cleanupPoliciesTable(policies []CleanupPolicy) Tabular {
+	t := table{header: []string{"NAME", "FORMAT", "LAST BLOB UPDATED", "LAST DOWNLOADED", "RELEASE TYPE", "ASSET REGEX"}}
+	for _, p := range policies {
+		t.rows = append(t.rows, []string{p.Name, p.Format, strconv.Itoa(p.CriteriaLastBlobUpdated), strconv.Itoa(p.CriteriaLastDownloaded), p.CriteriaReleaseType, p.CriteriaAssetRegex})
+	}
+	return t
+}
+
+func routingRulesTable(rules []RoutingRule) Tabular {
+	t := table{header: []string{"NAME", "MODE", "MATCHERS", "DESCRIPTION"}}
+	for _, r := range rules {
+		t.rows = append(t.rows, []string{r.Name, r.Mode, strings.Join(r.Matchers, ","), r.Description})
+	}
+	return t
+}
+
+func usersTable(users []User) Tabular {
+	t := table{header: []string{"ID", "NAME", "EMAIL", "SOURCE", "STATUS", "ROLES"}}
+	for _, u := range users {
+		t.rows = append(t.rows, []string{u.UserID, strings.TrimSpace(u.FirstName + " " + u.LastName), u.EmailAddress, u.Source, u.Status, strings.Join(u.Roles, ",")})
+	}
+	return t
+}
+
+func ldapServersTable(servers []LDAPServer) Tabular {
+	t := table{header: []string{"ORDER", "NAME", "URL", "AUTH SCHEME", "GROUPS AS ROLES"}}
+	for _, s := range servers {
+		url := fmt.Sprintf("%s://%s:%d/%s", s.Protocol, s.Host, s.Port, s.SearchBase)
+		t.rows = append(t.rows, []string{strconv.Itoa(s.Order), s.Name, url, s.AuthScheme, strconv.FormatBool(s.LDAPGroupsAsRoles)})
+	}
+	return t
+}
+
+func anonymousRepositoriesTable(repositories []AnonymousRepository) Tabular {
+	t := table{header: []string{"NAME", "FORMAT", "PARTIAL", "PRIVILEGES"}}
+	for _, r := range repositories {
+		t.rows = append(t.rows, []string{r.Name, r.Format, strconv.FormatBool(r.Partial), strings.Join(r.Privileges, ",")})
+	}
+	return t
+}
+
+func changesTable(changes []Change) Tabular {
+	t := table{header: []string{"RESOURCE", "NAME", "ACTION"}}
+	for _, c := range changes {
+		t.rows = append(t.rows, []string{c.Resource, c.Name, c.Action})
+	}
+	return t
+}
@@ -1,20 +1,22 @@
 package nxrm
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"os"
-	regexp2 "regexp"
 	"strings"
 )
 
+// Repository is a repository of nexus. Online is not set when the state of the repository is unknown,
+// repositories are created online unless Online is set to false
 type Repository struct {
 	Name       string     `json:"name"`
 	URL        string     `json:"url"`
 	Type       string     `json:"type"`
 	Format     string     `json:"format"`
 	Recipe     string     `json:"recipe"`
+	Online     *bool      `json:"online,omitempty"`
 	Attributes Attributes `json:"attributes"`
 }
 
@@ -28,6 +30,12 @@ type Attributes struct {
 	Docker        Docker        `json:"docker"`
 	DockerProxy   DockerProxy   `json:"dockerProxy"`
 	Cleanup       Cleanup       `json:"cleanup"`
+	Yum           Yum           `json:"yum"`
+	Apt           Apt           `json:"apt"`
+	AptSigning    AptSigning    `json:"aptSigning"`
+	NugetProxy    NugetProxy    `json:"nugetProxy"`
+	Bower         Bower         `json:"bower"`
+	RoutingRule   string        `json:"routingRule"`
 }
 
 type Storage struct {
@@ -79,295 +87,402 @@ type NegetiveCache struct {
 	TimeToLive float64 `json:"timeToLive"`
 }
 
+// Cleanup contains the cleanup policies of a repository. nexus 3.19 and later store a set of policies,
+// the older versions store a single policy
 type Cleanup struct {
-	PolicyName string `json:"policyName"`
+	PolicyNames []string `json:"policyName"`
 }
 
-func ListRepositories(name, format string) {
-	var repositoryList []string
+// UnmarshalJSON decodes the cleanup policies of a repository, which are either a list or a single policy name
+func (c *Cleanup) UnmarshalJSON(data []byte) error {
+	var cleanup struct {
+		PolicyName json.RawMessage `json:"policyName"`
+	}
+	if err := json.Unmarshal(data, &cleanup); err != nil {
+		return err
+	}
+	c.PolicyNames = nil
+	if len(cleanup.PolicyName) == 0 || string(cleanup.PolicyName) == "null" {
+		return nil
+	}
+	var policyName string
+	if err := json.Unmarshal(cleanup.PolicyName, &policyName); err == nil {
+		if policyName != "" {
+			c.PolicyNames = []string{policyName}
+		}
+		return nil
+	}
+	return json.Unmarshal(cleanup.PolicyName, &c.PolicyNames)
+}
+
+// Yum contains the attributes of a yum hosted repository
+type Yum struct {
+	RepodataDepth int    `json:"repodataDepth"`
+	DeployPolicy  string `json:"deployPolicy"`
+}
+
+// Apt contains the attributes of an apt repository. Flat only applies to proxy repositories
+type Apt struct {
+	Distribution string `json:"distribution"`
+	Flat         bool   `json:"flat"`
+}
+
+// AptSigning contains the PGP key used to sign the metadata of an apt hosted repository
+type AptSigning struct {
+	Keypair    string `json:"keypair"`
+	Passphrase string `json:"passphrase"`
+}
+
+// NugetProxy contains the attributes of a nuget proxy repository
+type NugetProxy struct {
+	NugetVersion         string `json:"nugetVersion"`
+	QueryCacheItemMaxAge int    `json:"queryCacheItemMaxAge"`
+}
+
+// Bower contains the attributes of a bower proxy repository
+type Bower struct {
+	RewritePackageUrls bool `json:"rewritePackageUrls"`
+}
+
+// AttributesOption sets format specific attributes of a repository
+type AttributesOption func(attributes *Attributes)
+
+// AptAttributes sets the distribution of an apt repository eg: bionic. flat only applies to proxy repositories
+func AptAttributes(distribution string, flat bool) AttributesOption {
+	return func(attributes *Attributes) {
+		attributes.Apt = Apt{Distribution: distribution, Flat: flat}
+	}
+}
+
+// AptSigningAttributes sets the PGP key used to sign the metadata of an apt hosted repository
+func AptSigningAttributes(keypair, passphrase string) AttributesOption {
+	return func(attributes *Attributes) {
+		attributes.AptSigning = AptSigning{Keypair: keypair, Passphrase: passphrase}
+	}
+}
+
+// YumAttributes sets the depth of the repodata directory and the deploy policy of a yum hosted repository
+func YumAttributes(repodataDepth int, deployPolicy string) AttributesOption {
+	return func(attributes *Attributes) {
+		attributes.Yum = Yum{RepodataDepth: repodataDepth, DeployPolicy: deployPolicy}
+	}
+}
+
+// NugetProxyAttributes sets the version of the nuget protocol eg: V3 and the maximum age of the query cache in seconds
+func NugetProxyAttributes(nugetVersion string, queryCacheItemMaxAge int) AttributesOption {
+	return func(attributes *Attributes) {
+		attributes.NugetProxy = NugetProxy{NugetVersion: nugetVersion, QueryCacheItemMaxAge: queryCacheItemMaxAge}
+	}
+}
 
+// ListRepositories prints the details of a repository when a name is provided,
+// otherwise prints the names of all the repositories filtered by format
+func (c *Client) ListRepositories(ctx context.Context, name, format string) error {
 	if name != "" {
-		repository := getRepository(name)
+		repository, err := c.GetRepository(ctx, name)
+		if err != nil {
+			return err
+		}
 		fmt.Printf("Name: %s\nRecipe: %s\nURL: %s\n", repository.Name, repository.Recipe, repository.URL)
-	} else if name == "" && format == "" {
-		repositoryList = getRepositoryList()
-	} else {
-		repositoryList = getRepositoryListByFormat(validateRepositoryFormat(format))
+		return nil
 	}
-	if name == "" {
-		printStringSlice(repositoryList)
-		fmt.Printf("Number of repositories : %d\n", len(repositoryList))
+	repositories, err := c.GetRepositories(ctx, ListOptions{Format: format})
+	if err != nil {
+		return err
+	}
+	for _, r := range repositories {
+		fmt.Println(r.Name)
 	}
+	fmt.Printf("Number of repositories : %d\n", len(repositories))
+	return nil
 }
 
-func CreateHosted(name, blobStoreName, format string, dockerHttpPort, dockerHttpsPort float64, releases bool) {
-	if name == "" || format == "" {
-		log.Printf("%s : %s", getfuncName(), repoNameFormatRequiredInfo)
-		os.Exit(1)
+// GetRepositories returns the repositories matching the options
+func (c *Client) GetRepositories(ctx context.Context, opts ListOptions) ([]Repository, error) {
+	if err := opts.validate(ResourceRepository); err != nil {
+		return nil, err
 	}
-	format = validateRepositoryFormat(format)
-
-	var attributes Attributes
-	recipe := fmt.Sprintf("%s-hosted", format)
-	storage := Storage{BlobStoreName: getBlobStoreName(blobStoreName), StrictContentTypeValidation: true, WritePolicy: getWritePolicy(releases)}
-
-	if format == "maven2" {
-		maven := Maven{VersionPolicy: getVersionPolicy(releases), LayoutPolicy: "STRICT"}
-		attributes = Attributes{Storage: storage, Maven: maven}
-	} else if format == "docker" {
-		if dockerHttpPort == 0 && dockerHttpsPort == 0 {
-			log.Printf("%s : %s", getfuncName(), dockerPortsInfo)
-			os.Exit(1)
+	repositories, err := c.getRepositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Repository
+	for _, r := range repositories {
+		if opts.match(r.Name, r.Type, r.Format) {
+			result = append(result, r)
 		}
-		docker := Docker{HTTPPort: dockerHttpPort, HTTPSPort: dockerHttpsPort, ForceBasicAuth: true, V1Enabled: false}
-		attributes = Attributes{Storage: storage, Docker: docker}
-	} else {
-		attributes = Attributes{Storage: storage}
 	}
+	return result, nil
+}
 
-	repository := Repository{Name: name, Format: format, Recipe: recipe, Attributes: attributes}
-	payload, err := json.Marshal(repository)
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(createHostedRepoScript, string(payload))
-	printCreateRepoStatus(name, result.Status)
+// GetRepository returns the details of a repository including its attributes
+func (c *Client) GetRepository(ctx context.Context, name string) (Repository, error) {
+	return c.getRepository(ctx, name)
 }
 
-func CreateProxy(name, blobStoreName, format, remoteURL, proxyUsername, proxyPassword string, dockerHttpPort, dockerHttpsPort float64, releases bool) {
-	if name == "" || format == "" {
-		log.Printf("%s : %s", getfuncName(), repoNameFormatRequiredInfo)
-		os.Exit(1)
-	} else if remoteURL == "" {
-		log.Printf("%s : %s", getfuncName(), proxyRepoRequiredInfo)
-		os.Exit(1)
+// CreateHosted creates a hosted repository. The format specific attributes eg: of apt and yum are set using opts.
+// Use CreateRepository with a HostedRepository to set every attribute
+func (c *Client) CreateHosted(ctx context.Context, name, blobStoreName, format string, dockerHttpPort, dockerHttpsPort float64, releases bool, opts ...AttributesOption) error {
+	spec := NewHostedRepository(name, format)
+	spec.Storage.BlobStoreName = getBlobStoreName(blobStoreName)
+	spec.Storage.WritePolicy = getWritePolicy(releases)
+	spec.Maven.VersionPolicy = getVersionPolicy(releases)
+	spec.Docker.HTTPPort, spec.Docker.HTTPSPort = dockerHttpPort, dockerHttpsPort
+	repository, err := buildRepository(spec.Name, spec.Format, "hosted", spec.attributes(), opts)
+	if err != nil {
+		return err
 	}
-	format = validateRepositoryFormat(format)
-	validateProxyAuthInfo(proxyUsername, proxyPassword)
-	validateRemoteURL(remoteURL)
+	return c.createRepository(ctx, repository)
+}
 
-	var attributes Attributes
-	recipe := fmt.Sprintf("%s-proxy", format)
-	storage := Storage{BlobStoreName: getBlobStoreName(blobStoreName), StrictContentTypeValidation: true, WritePolicy: getWritePolicy(releases)}
-	proxy := Proxy{RemoteURL: remoteURL, ContentMaxAge: -1, MetadataMaxAge: 1440}
-	proxyAuth := HttpClientAuth{Username: proxyUsername, Password: proxyPassword}
-	proxyHttpClient := HttpClient{Blocked: false, AutoBlock: true, Authentication: proxyAuth}
-	negetiveCache := NegetiveCache{Enabled: true, TimeToLive: 1440}
-
-	if format == "maven2" {
-		maven := Maven{VersionPolicy: getVersionPolicy(releases), LayoutPolicy: "STRICT"}
-		attributes = Attributes{Storage: storage, Maven: maven, Proxy: proxy, Httpclient: proxyHttpClient, NegativeCache: negetiveCache}
-	} else if format == "docker" {
-		if dockerHttpPort == 0 && dockerHttpsPort == 0 {
-			log.Printf("%s : %s", getfuncName(), dockerPortsInfo)
-			os.Exit(1)
-		}
-		docker := Docker{HTTPPort: dockerHttpPort, HTTPSPort: dockerHttpsPort, ForceBasicAuth: true, V1Enabled: false}
-		dockerProxy := DockerProxy{IndexType: "REGISTRY"}
-		attributes = Attributes{Storage: storage, Docker: docker, Proxy: proxy, DockerProxy: dockerProxy, Httpclient: proxyHttpClient, NegativeCache: negetiveCache}
-	} else {
-		storage := Storage{BlobStoreName: getBlobStoreName(blobStoreName), StrictContentTypeValidation: true, WritePolicy: getWritePolicy(releases)}
-		attributes = Attributes{Storage: storage, Proxy: proxy, Httpclient: proxyHttpClient, NegativeCache: negetiveCache}
+// CreateProxy creates a proxy repository. The format specific attributes eg: of apt and nuget are set using opts.
+// Use CreateRepository with a ProxyRepository to set every attribute
+func (c *Client) CreateProxy(ctx context.Context, name, blobStoreName, format, remoteURL, proxyUsername, proxyPassword string, dockerHttpPort, dockerHttpsPort float64, releases bool, opts ...AttributesOption) error {
+	spec := NewProxyRepository(name, format, remoteURL)
+	spec.Storage.BlobStoreName = getBlobStoreName(blobStoreName)
+	spec.Storage.WritePolicy = getWritePolicy(releases)
+	spec.Maven.VersionPolicy = getVersionPolicy(releases)
+	spec.Docker.HTTPPort, spec.Docker.HTTPSPort = dockerHttpPort, dockerHttpsPort
+	spec.Httpclient.Authentication = HttpClientAuth{Username: proxyUsername, Password: proxyPassword}
+	repository, err := buildRepository(spec.Name, spec.Format, "proxy", spec.attributes(), opts)
+	if err != nil {
+		return err
 	}
-
-	repository := Repository{Name: name, Format: format, Recipe: recipe, Attributes: attributes}
-	payload, err := json.Marshal(repository)
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(createProxyRepoScript, string(payload))
-	printCreateRepoStatus(name, result.Status)
+	return c.createRepository(ctx, repository)
 }
 
-func CreateGroup(name, blobStoreName, format, repoMembers string, dockerHttpPort, dockerHttpsPort float64, releases bool) {
+// CreateGroup creates a group repository. The format specific attributes are set using opts.
+// The members which do not exist or have another format are skipped.
+// Use CreateRepository with a GroupRepository to set every attribute
+func (c *Client) CreateGroup(ctx context.Context, name, blobStoreName, format, repoMembers string, dockerHttpPort, dockerHttpsPort float64, releases bool, opts ...AttributesOption) error {
 	if name == "" || format == "" {
-		log.Printf("%s : %s", getfuncName(), repoNameFormatRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, repoNameFormatRequiredInfo)
 	} else if repoMembers == "" {
-		log.Printf("%s : %s", getfuncName(), groupRepoRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, groupRepoRequiredInfo)
 	}
-	format = validateRepositoryFormat(format)
-	validList := validateGroupMembers(repoMembers, format)
-
-	var attributes Attributes
-	recipe := fmt.Sprintf("%s-group", format)
-	storage := Storage{BlobStoreName: getBlobStoreName(blobStoreName), StrictContentTypeValidation: true, WritePolicy: getWritePolicy(releases)}
-	group := Group{MemberNames: validList}
-
-	if format == "maven2" {
-		maven := Maven{VersionPolicy: getVersionPolicy(releases), LayoutPolicy: "STRICT"}
-		attributes = Attributes{Storage: storage, Maven: maven, Group: group}
-	} else if format == "docker" {
-		if dockerHttpPort == 0 && dockerHttpsPort == 0 {
-			log.Printf("%s : %s", getfuncName(), dockerPortsInfo)
-			os.Exit(1)
-		}
-		docker := Docker{HTTPPort: dockerHttpPort, HTTPSPort: dockerHttpsPort, ForceBasicAuth: true, V1Enabled: false}
-		attributes = Attributes{Storage: storage, Docker: docker, Group: group}
-	} else {
-		storage := Storage{BlobStoreName: getBlobStoreName(blobStoreName), StrictContentTypeValidation: true, WritePolicy: getWritePolicy(releases)}
-		attributes = Attributes{Storage: storage, Group: group}
+	format, err := validateRepositoryFormat(format)
+	if err != nil {
+		return err
+	}
+	if err := validateFormatType(format, "group"); err != nil {
+		return err
+	}
+	validList, err := c.validateGroupMembers(ctx, repoMembers, format)
+	if err != nil {
+		return err
 	}
 
-	repository := Repository{Name: name, Format: format, Recipe: recipe, Attributes: attributes}
-	payload, err := json.Marshal(repository)
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(createGroupRepoScript, string(payload))
-	printCreateRepoStatus(name, result.Status)
+	spec := NewGroupRepository(name, format, validList...)
+	spec.Storage.BlobStoreName = getBlobStoreName(blobStoreName)
+	spec.Storage.WritePolicy = getWritePolicy(releases)
+	spec.Maven.VersionPolicy = getVersionPolicy(releases)
+	spec.Docker.HTTPPort, spec.Docker.HTTPSPort = dockerHttpPort, dockerHttpsPort
+	repository, err := buildRepository(spec.Name, spec.Format, "group", spec.attributes(), opts)
+	if err != nil {
+		return err
+	}
+	return c.createRepository(ctx, repository)
 }
 
-func AddMembersToGroup(name, repoMembers string) {
+func (c *Client) AddMembersToGroup(ctx context.Context, name, repoMembers string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), name)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	} else if repoMembers == "" {
-		log.Printf("%s : %s", getfuncName(), groupRepoRequiredInfo)
-		os.Exit(1)
-	}
-	if repositoryExists(name) {
-		repo := getRepository(name)
-		validateGroupRepo(repo)
-		format := repo.Format
-		validList := validateGroupMembers(repoMembers, format)
-		currentMembers := repo.Attributes.Group.MemberNames
-		for _, newMember := range validList {
-			if entryExists(currentMembers, newMember) {
-				log.Printf(groupMemberAlreadyExistsInfo, newMember, name)
-			} else if newMember == name {
-				log.Printf(cannotBeSameRepoInfo, newMember, name)
-			} else {
-				log.Printf(groupMemberAddSuccessInfo, newMember, name)
-				currentMembers = append(currentMembers, newMember)
-			}
+		return newError(ErrInvalidInput, groupRepoRequiredInfo)
+	}
+	repo, err := c.getRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := validateGroupRepo(repo); err != nil {
+		return err
+	}
+	format := repo.Format
+	validList, err := c.validateGroupMembers(ctx, repoMembers, format)
+	if err != nil {
+		return err
+	}
+	currentMembers := repo.Attributes.Group.MemberNames
+	var added []string
+	for _, newMember := range validList {
+		if entryExists(currentMembers, newMember) {
+			c.logger.Printf(groupMemberAlreadyExistsInfo, newMember, name)
+		} else if newMember == name {
+			c.logger.Printf(cannotBeSameRepoInfo, newMember, name)
+		} else {
+			currentMembers = append(currentMembers, newMember)
+			added = append(added, newMember)
 		}
-		repo.Attributes.Group = Group{MemberNames: currentMembers}
-		repository := Repository{Name: name, Format: format, Attributes: repo.Attributes}
-		payload, err := json.Marshal(repository)
-		logJsonMarshalError(err, getfuncName())
-		result := RunScript(updateGroupMembersScript, string(payload))
-		printUpdateRepoStatus(name, result.Status)
-	} else {
-		log.Printf(repositoryNotFoundInfo, name)
 	}
+	repo.Attributes.Group = Group{MemberNames: currentMembers}
+	if err := c.updateGroupMembers(ctx, Repository{Name: name, Format: format, Online: repo.Online, Attributes: repo.Attributes}); err != nil {
+		return err
+	}
+	for _, member := range added {
+		c.logger.Printf(groupMemberAddSuccessInfo, member, name)
+	}
+	return nil
 }
 
-func RemoveMembersFromGroup(name, repoMembers string) {
+func (c *Client) RemoveMembersFromGroup(ctx context.Context, name, repoMembers string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	} else if repoMembers == "" {
-		log.Printf("%s : %s", getfuncName(), groupRepoRequiredInfo)
-		os.Exit(1)
-	}
-	if repositoryExists(name) {
-		repo := getRepository(name)
-		validateGroupRepo(repo)
-		format := repo.Format
-		validList := validateGroupMembers(repoMembers, format)
-		currentMembers := repo.Attributes.Group.MemberNames
-		for _, newMember := range validList {
-			if !entryExists(currentMembers, newMember) {
-				log.Printf(groupMemberRemoveNotFoundInfo, newMember, name)
-			} else if newMember == name {
-				log.Printf(cannotBeSameRepoInfo, newMember, name)
-			} else {
-				log.Printf(groupMemberRemoveSuccessInfo, newMember, name)
-				currentMembers = removeEntryFromSlice(currentMembers, newMember)
-			}
+		return newError(ErrInvalidInput, groupRepoRequiredInfo)
+	}
+	repo, err := c.getRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := validateGroupRepo(repo); err != nil {
+		return err
+	}
+	format := repo.Format
+	validList, err := c.validateGroupMembers(ctx, repoMembers, format)
+	if err != nil {
+		return err
+	}
+	currentMembers := repo.Attributes.Group.MemberNames
+	var removed []string
+	for _, newMember := range validList {
+		if !entryExists(currentMembers, newMember) {
+			c.logger.Printf(groupMemberRemoveNotFoundInfo, newMember, name)
+		} else if newMember == name {
+			c.logger.Printf(cannotBeSameRepoInfo, newMember, name)
+		} else {
+			currentMembers = removeEntryFromSlice(currentMembers, newMember)
+			removed = append(removed, newMember)
 		}
-		repo.Attributes.Group = Group{MemberNames: currentMembers}
-		repository := Repository{Name: name, Format: format, Attributes: repo.Attributes}
-		payload, err := json.Marshal(repository)
-		logJsonMarshalError(err, getfuncName())
-		result := RunScript(updateGroupMembersScript, string(payload))
-		printUpdateRepoStatus(name, result.Status)
-	} else {
-		log.Printf(repositoryNotFoundInfo, name)
 	}
+	repo.Attributes.Group = Group{MemberNames: currentMembers}
+	if err := c.updateGroupMembers(ctx, Repository{Name: name, Format: format, Online: repo.Online, Attributes: repo.Attributes}); err != nil {
+		return err
+	}
+	for _, member := range removed {
+		c.logger.Printf(groupMemberRemoveSuccessInfo, member, name)
+	}
+	return nil
 }
 
-func DeleteRepository(name string) {
+// UpdateRepository changes the attributes of a repository. update is called with the current attributes of the
+// repository and sets the fields to change, the other fields keep their current value.
+// The attributes are validated like when a repository is created. The attributes of nexus which are not modeled by
+// Attributes keep their value, like the password of a proxy repository which is not returned by nexus.
+// The REST API cannot keep the password, hence update must set it when the repository uses credentials and the
+// REST backend is used
+func (c *Client) UpdateRepository(ctx context.Context, name string, update func(attributes *Attributes)) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	if update == nil {
+		return newError(ErrInvalidInput, updateFuncRequiredInfo)
 	}
-	payload, err := json.Marshal(Repository{Name: name})
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(deleteRepoScript, string(payload))
-	printDeleteRepoStatus(name, result.Status)
+	current, err := c.getRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+	// the attributes are copied so that update does not change the slices of current
+	var attributes Attributes
+	if err := convert(current.Attributes, &attributes); err != nil {
+		return err
+	}
+	update(&attributes)
+	repository := current
+	repository.Attributes = attributes
+	if err := c.validateAttributes(ctx, current, repository, nil); err != nil {
+		return err
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.updateRepository(ctx, repository); err != nil {
+		return err
+	}
+	c.logger.Printf(repoUpdatedStatus, name)
+	return nil
 }
 
-func getRepository(name string) Repository {
+func (c *Client) DeleteRepository(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
-	var result ScriptResult
-	if repositoryExists(name) {
-		payload, err := json.Marshal(Repository{Name: name})
-		logJsonMarshalError(err, getfuncName())
-		result = RunScript(getRepoScript, string(payload))
-		if result.Status != successStatus {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
-		}
-	} else {
-		log.Printf("%s : %s", getfuncName(), fmt.Sprintf(repositoryNotFoundInfo, name))
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
 	}
-	return Repository{Name: result.Name, URL: result.URL, Type: result.Type, Format: result.Format, Recipe: result.Recipe, Attributes: result.Attributes}
+	if err := b.deleteRepository(ctx, name); err != nil {
+		return err
+	}
+	c.logger.Printf(repoDeletedInfo, name)
+	return nil
 }
 
-func getRepositories() []Repository {
-	url := fmt.Sprintf("%s/%s/%s", NexusURL, apiBase, repositoryPath)
-	var repositories []Repository
-	req := createBaseRequest("GET", url, RequestBody{})
-	respBody, status := httpRequest(req)
-	if status != successStatus {
-		log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-		os.Exit(1)
-	} else {
-		err := json.Unmarshal(respBody, &repositories)
-		logJsonUnmarshalError(err, getfuncName())
+func (c *Client) createRepository(ctx context.Context, repository Repository) error {
+	if err := c.validateReferences(ctx, repository); err != nil {
+		return err
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.createRepository(ctx, repository); err != nil {
+		return err
+	}
+	c.logger.Printf(repoCreatedInfo, repository.Name)
+	if c.realmWarnings {
+		c.warnInactiveRealm(ctx, repository)
 	}
-	return repositories
+	return nil
 }
 
-func getRepositoryList() []string {
-	var repositoryList []string
-	repositories := getRepositories()
-	for _, r := range repositories {
-		repositoryList = append(repositoryList, r.Name)
+func (c *Client) updateGroupMembers(ctx context.Context, repository Repository) error {
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.updateGroupMembers(ctx, repository); err != nil {
+		return err
 	}
-	return repositoryList
+	c.logger.Printf(repoUpdatedStatus, repository.Name)
+	return nil
 }
 
-func getRepositoryListByFormat(format string) []string {
-	var repositoryList []string
-	repositories := getRepositories()
-	for _, r := range repositories {
-		if format == r.Format {
-			repositoryList = append(repositoryList, r.Name)
-		}
+func (c *Client) getRepository(ctx context.Context, name string) (Repository, error) {
+	if name == "" {
+		return Repository{}, newError(ErrInvalidInput, nameRequiredInfo)
 	}
-	return repositoryList
+	b, err := c.backend(ctx)
+	if err != nil {
+		return Repository{}, err
+	}
+	return b.getRepository(ctx, name)
 }
 
-func repositoryExists(name string) bool {
-	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
-	var isExists bool
-	payload, err := json.Marshal(Repository{Name: name})
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(getRepoScript, string(payload))
-	if result.Status == successStatus {
-		isExists = true
-	} else if result.Status == notFoundStatus {
-		isExists = false
-	} else {
-		log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-		os.Exit(1)
+func (c *Client) getRepositories(ctx context.Context) ([]Repository, error) {
+	url := fmt.Sprintf("%s/%s/%s", c.baseURL, apiBase, repositoryPath)
+	var repositories []Repository
+	respBody, status, err := c.doRequest(ctx, "GET", url, RequestBody{})
+	if err != nil {
+		return nil, err
+	}
+	if status != successStatus {
+		return nil, newAPIError("GET", url, status, respBody)
+	}
+	if err := json.Unmarshal(respBody, &repositories); err != nil {
+		return nil, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	return repositories, nil
+}
+
+func (c *Client) repositoryExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.getRepository(ctx, name)
+	if errors.Is(err, ErrRepositoryNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
 	}
-	return isExists
+	return true, nil
 }
 
 func getBlobStoreName(blobStoreName string) string {
@@ -397,104 +512,210 @@ func getWritePolicy(releases bool) string {
 	return writePolicy
 }
 
-func validateRepositoryFormat(format string) string {
+func validateRepositoryFormat(format string) (string, error) {
 	if format == "" {
-		log.Printf("%s : %s", getfuncName(), repoFormatRequiredInfo)
-		os.Exit(1)
-	}
-	formatChoice := map[string]bool{"": true}
-	for _, repoFormat := range RepoFormats {
-		formatChoice[repoFormat] = true
+		return "", newError(ErrInvalidInput, repoFormatRequiredInfo)
 	}
-	if _, validChoice := formatChoice[format]; !validChoice {
-		log.Printf("%s : %s", getfuncName(), fmt.Sprintf(RepoFormatNotValidInfo, format, RepoFormats))
-		os.Exit(1)
+	if !entryExists(RepoFormats, format) {
+		return "", newError(ErrInvalidInput, RepoFormatNotValidInfo, format, RepoFormats)
 	}
 	if format == "maven" {
-		return "maven2"
+		return "maven2", nil
 	}
-	return format
+	return format, nil
 }
 
-func validateProxyAuthInfo(proxyUsername, proxyPassword string) {
-	if proxyUsername == "" && proxyPassword == "" {
-		return
-	} else if proxyUsername != "" && proxyPassword != "" {
-		return
-	} else {
-		log.Printf("%s : %s\n", getfuncName(), proxyCredsNotValidInfo)
-		os.Exit(1)
+func validateProxyAuthInfo(proxyUsername, proxyPassword string) error {
+	if (proxyUsername == "") != (proxyPassword == "") {
+		return newError(ErrInvalidInput, proxyCredsNotValidInfo)
 	}
+	return nil
 }
 
-func validateRemoteURL(url string) {
-	httpRegex, _ := regexp2.Compile(`^(http://).*`)
-	httpsRegex, _ := regexp2.Compile(`^(https://).*`)
-	if httpRegex.MatchString(url) || httpsRegex.MatchString(url) {
-		return
-	} else {
-		log.Printf("%s : %s", getfuncName(), fmt.Sprintf(remoteURLNotValidInfo, url))
-		os.Exit(1)
+func validateRemoteURL(url string) error {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		return nil
 	}
+	return newError(ErrInvalidInput, remoteURLNotValidInfo, url)
 }
 
-func validateGroupRepo(repo Repository) {
-	if strings.Contains(repo.Recipe, "group") {
-		return
-	} else {
-		log.Printf(notAGroupRepoInfo, repo.Name)
-		os.Exit(1)
+func validateGroupRepo(repo Repository) error {
+	if !strings.Contains(repo.Recipe, "group") {
+		return newError(ErrInvalidInput, notAGroupRepoInfo, repo.Name)
 	}
+	return nil
 }
 
-func validateGroupMembers(repoMembers, format string) []string {
-	var validList []string
-	repoMembersList := strings.Split(strings.Replace(repoMembers, " ", "", -1), ",")
-	for _, repoMember := range repoMembersList {
-		if repositoryExists(repoMember) {
-			repoDetails := getRepository(repoMember)
-			if strings.Contains(repoDetails.Recipe, format) {
-				validList = append(validList, repoMember)
-			} else {
-				log.Printf(groupMemberInvalidFormatInfo, repoMember, format)
+// validateAttributes validates the attributes of a repository which is updated from current.
+// pending are the repositories which are created before the update, see validateGroupMemberNames
+func (c *Client) validateAttributes(ctx context.Context, current, repository Repository, pending map[string]Repository) error {
+	if err := validateRepository(repository); err != nil {
+		return err
+	}
+	switch repositoryType(repository) {
+	case "proxy":
+		// nexus does not return the password, hence the credentials are only validated when they change and the
+		// stored password is kept when the password is not set
+		auth := repository.Attributes.Httpclient.Authentication
+		if auth != current.Attributes.Httpclient.Authentication {
+			if err := validateProxyAuthInfo(auth.Username, auth.Password); err != nil {
+				return err
 			}
-		} else {
-			log.Printf(groupMemberNotFoundInfo, repoMember)
+		}
+	case "group":
+		if err := c.validateGroupMemberNames(ctx, repository, pending); err != nil {
+			return err
 		}
 	}
-	if len(validList) < 1 {
-		log.Printf("%s : %s\n", getfuncName(), groupMemberRequiredInfo)
-		os.Exit(1)
+	if routingRule := repository.Attributes.RoutingRule; routingRule != current.Attributes.RoutingRule {
+		return c.validateRepositoryRoutingRule(ctx, routingRule)
 	}
-	return validList
+	return nil
 }
 
-func printCreateRepoStatus(name, status string) {
-	if status == successStatus {
-		log.Printf(repoCreatedInfo, name)
-	} else if status == foundStatus {
-		log.Printf(repoExistsInfo, name)
-	} else {
-		log.Printf(repoCreateErrorInfo, setVerboseInfo)
+// validateReferences checks that the blob store and the routing rule of a repository which is created exist
+func (c *Client) validateReferences(ctx context.Context, repository Repository) error {
+	if err := c.validateBlobStore(ctx, repository.Attributes.Storage.BlobStoreName); err != nil {
+		return err
 	}
+	return c.validateRepositoryRoutingRule(ctx, repository.Attributes.RoutingRule)
 }
 
-func printUpdateRepoStatus(name, status string) {
-	if status == successStatus {
-		log.Printf(repoUpdatedStatus, name)
-	} else if status == notFoundStatus {
-		log.Printf(repositoryNotFoundInfo, name)
-	} else {
-		log.Printf(repoUpdateErrorInfo, setVerboseInfo)
+// validateRepository validates the attributes of a repository which do not require a lookup in nexus
+func validateRepository(repository Repository) error {
+	attributes := repository.Attributes
+	repoType := repositoryType(repository)
+	// only hosted repositories require a write policy
+	if writePolicy := attributes.Storage.WritePolicy; writePolicy != "" || repoType == "hosted" {
+		if !entryExists(WritePolicies, writePolicy) {
+			return newError(ErrInvalidInput, writePolicyNotValidInfo, writePolicy, WritePolicies)
+		}
 	}
+	switch repoType {
+	case "proxy":
+		if attributes.Proxy.RemoteURL == "" {
+			return newError(ErrInvalidInput, proxyRepoRequiredInfo)
+		}
+		if err := validateRemoteURL(attributes.Proxy.RemoteURL); err != nil {
+			return err
+		}
+		if attributes.Proxy.ContentMaxAge < -1 || attributes.Proxy.MetadataMaxAge < -1 {
+			return newError(ErrInvalidInput, maxAgeNotValidInfo)
+		}
+		if attributes.NegativeCache.TimeToLive < 0 {
+			return newError(ErrInvalidInput, negativeCacheTTLNotValidInfo, attributes.NegativeCache.TimeToLive)
+		}
+	case "group":
+		if len(attributes.Group.MemberNames) < 1 {
+			return newError(ErrInvalidInput, groupMemberRequiredInfo)
+		}
+	}
+	switch repository.Format {
+	case "maven2":
+		if !entryExists(VersionPolicies, attributes.Maven.VersionPolicy) {
+			return newError(ErrInvalidInput, versionPolicyNotValidInfo, attributes.Maven.VersionPolicy, VersionPolicies)
+		}
+		if !entryExists(LayoutPolicies, attributes.Maven.LayoutPolicy) {
+			return newError(ErrInvalidInput, layoutPolicyNotValidInfo, attributes.Maven.LayoutPolicy, LayoutPolicies)
+		}
+	case "docker":
+		if attributes.Docker.HTTPPort == 0 && attributes.Docker.HTTPSPort == 0 {
+			return newError(ErrInvalidInput, dockerPortsInfo)
+		}
+		for _, port := range []float64{attributes.Docker.HTTPPort, attributes.Docker.HTTPSPort} {
+			if port < 0 || port > 65535 {
+				return newError(ErrInvalidInput, dockerPortNotValidInfo, port)
+			}
+		}
+	}
+	return validateFormatAttributes(repository)
 }
 
-func printDeleteRepoStatus(name, status string) {
-	if status == successStatus {
-		log.Printf(repoDeletedInfo, name)
-	} else if status == notFoundStatus {
-		log.Printf(repositoryNotFoundInfo, name)
-	} else {
-		log.Printf(repoDeleteErrorInfo, setVerboseInfo)
+// validateFormatType checks that repositories of a type can be created for a format
+func validateFormatType(format, repoType string) error {
+	if repoTypes, ok := formatRepoTypes[format]; ok && !entryExists(repoTypes, repoType) {
+		return newError(ErrInvalidInput, formatTypeNotSupportedInfo, repoType, format, repoTypes)
+	}
+	return nil
+}
+
+// validateFormatAttributes validates the attributes which are specific to the format of a repository
+func validateFormatAttributes(repository Repository) error {
+	attributes := repository.Attributes
+	repoType := repositoryType(repository)
+	switch repository.Format {
+	case "apt":
+		if attributes.Apt.Distribution == "" {
+			return newError(ErrInvalidInput, aptDistributionRequiredInfo)
+		}
+		if repoType == "hosted" && attributes.AptSigning.Keypair == "" {
+			return newError(ErrInvalidInput, aptKeypairRequiredInfo)
+		}
+	case "yum":
+		if repoType != "hosted" {
+			break
+		}
+		if attributes.Yum.RepodataDepth < 0 || attributes.Yum.RepodataDepth > 5 {
+			return newError(ErrInvalidInput, repodataDepthNotValidInfo, attributes.Yum.RepodataDepth)
+		}
+		if deployPolicy := attributes.Yum.DeployPolicy; deployPolicy != "" && !entryExists(LayoutPolicies, deployPolicy) {
+			return newError(ErrInvalidInput, deployPolicyNotValidInfo, deployPolicy, LayoutPolicies)
+		}
+	case "nuget":
+		if nugetVersion := attributes.NugetProxy.NugetVersion; repoType == "proxy" && nugetVersion != "" && !entryExists(NugetVersions, nugetVersion) {
+			return newError(ErrInvalidInput, nugetVersionNotValidInfo, nugetVersion, NugetVersions)
+		}
+	}
+	return nil
+}
+
+// validateGroupMemberNames checks that the members of a group repository exist and have the format of the group.
+// The members which are in pending are not looked up in nexus, as they are created before the group
+func (c *Client) validateGroupMemberNames(ctx context.Context, repository Repository, pending map[string]Repository) error {
+	members := repository.Attributes.Group.MemberNames
+	if len(members) < 1 {
+		return newError(ErrInvalidInput, groupMemberRequiredInfo)
+	}
+	for _, member := range members {
+		if member == repository.Name {
+			return newError(ErrInvalidInput, cannotBeSameRepoInfo, member, repository.Name)
+		}
+		memberRepo, ok := pending[member]
+		if !ok {
+			var err error
+			memberRepo, err = c.getRepository(ctx, member)
+			if errors.Is(err, ErrRepositoryNotFound) {
+				return newError(ErrRepositoryNotFound, groupMemberNotFoundInfo, member)
+			} else if err != nil {
+				return err
+			}
+		}
+		if repositoryFormat(memberRepo) != repositoryFormat(repository) {
+			return newError(ErrInvalidInput, groupMemberInvalidFormatInfo, member, repository.Format)
+		}
+	}
+	return nil
+}
+
+func (c *Client) validateGroupMembers(ctx context.Context, repoMembers, format string) ([]string, error) {
+	var validList []string
+	repoMembersList := strings.Split(strings.Replace(repoMembers, " ", "", -1), ",")
+	for _, repoMember := range repoMembersList {
+		repoDetails, err := c.getRepository(ctx, repoMember)
+		if errors.Is(err, ErrRepositoryNotFound) {
+			c.logger.Printf(groupMemberNotFoundInfo, repoMember)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if repositoryFormat(repoDetails) == format {
+			validList = append(validList, repoMember)
+		} else {
+			c.logger.Printf(groupMemberInvalidFormatInfo, repoMember, format)
+		}
+	}
+	if len(validList) < 1 {
+		return nil, newError(ErrInvalidInput, groupMemberRequiredInfo)
 	}
+	return validList, nil
 }
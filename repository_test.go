@@ -0,0 +1,32 @@
+package nxrm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCleanupUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"list", `{"policyName": ["daily", "weekly"]}`, []string{"daily", "weekly"}},
+		{"single policy", `{"policyName": "daily"}`, []string{"daily"}},
+		{"empty policy", `{"policyName": ""}`, nil},
+		{"null", `{"policyName": null}`, nil},
+		{"missing", `{}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cleanup Cleanup
+			if err := json.Unmarshal([]byte(tt.data), &cleanup); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if !reflect.DeepEqual(cleanup.PolicyNames, tt.want) {
+				t.Errorf("Unmarshal() = %v, want %v", cleanup.PolicyNames, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package nxrm
+
+import (
+	"context"
+	"fmt"
+)
+
+// RepositorySpec describes a repository which is created or updated.
+// It is implemented by HostedRepository, ProxyRepository and GroupRepository
+type RepositorySpec interface {
+	// Repository validates the spec and returns the repository it describes
+	Repository() (Repository, error)
+}
+
+// HostedRepository describes a hosted repository. Use NewHostedRepository to get a spec with the defaults set
+type HostedRepository struct {
+	Name       string
+	Format     string
+	Storage    Storage
+	Cleanup    Cleanup
+	Maven      Maven
+	Docker     Docker
+	Yum        Yum
+	Apt        Apt
+	AptSigning AptSigning
+}
+
+// ProxyRepository describes a proxy repository. Use NewProxyRepository to get a spec with the defaults set
+type ProxyRepository struct {
+	Name          string
+	Format        string
+	Storage       Storage
+	Cleanup       Cleanup
+	Proxy         Proxy
+	Httpclient    HttpClient
+	NegativeCache NegetiveCache
+	Maven         Maven
+	Docker        Docker
+	DockerProxy   DockerProxy
+	Apt           Apt
+	NugetProxy    NugetProxy
+	Bower         Bower
+	RoutingRule   string
+}
+
+// GroupRepository describes a group repository. Use NewGroupRepository to get a spec with the defaults set
+type GroupRepository struct {
+	Name        string
+	Format      string
+	Storage     Storage
+	Group       Group
+	Maven       Maven
+	Docker      Docker
+	RoutingRule string
+}
+
+// NewHostedRepository returns the spec of a hosted repository with the defaults used by CreateHosted for a
+// snapshot repository. The docker ports and the attributes of apt must be set before creating the repository
+func NewHostedRepository(name, format string) *HostedRepository {
+	return &HostedRepository{
+		Name:    name,
+		Format:  format,
+		Storage: defaultStorage(),
+		Maven:   defaultMaven(),
+		Docker:  defaultDocker(),
+		Yum:     Yum{DeployPolicy: "STRICT"},
+	}
+}
+
+// NewProxyRepository returns the spec of a proxy repository of remoteURL with the defaults used by CreateProxy
+func NewProxyRepository(name, format, remoteURL string) *ProxyRepository {
+	return &ProxyRepository{
+		Name:          name,
+		Format:        format,
+		Storage:       defaultStorage(),
+		Proxy:         Proxy{RemoteURL: remoteURL, ContentMaxAge: -1, MetadataMaxAge: 1440},
+		Httpclient:    HttpClient{Blocked: false, AutoBlock: true},
+		NegativeCache: NegetiveCache{Enabled: true, TimeToLive: 1440},
+		Maven:         defaultMaven(),
+		Docker:        defaultDocker(),
+		DockerProxy:   DockerProxy{IndexType: "REGISTRY"},
+		NugetProxy:    NugetProxy{NugetVersion: "V3", QueryCacheItemMaxAge: 3600},
+		Bower:         Bower{RewritePackageUrls: true},
+	}
+}
+
+// NewGroupRepository returns the spec of a group repository of members with the defaults used by CreateGroup
+func NewGroupRepository(name, format string, members ...string) *GroupRepository {
+	return &GroupRepository{
+		Name:    name,
+		Format:  format,
+		Storage: defaultStorage(),
+		Group:   Group{MemberNames: members},
+		Maven:   defaultMaven(),
+		Docker:  defaultDocker(),
+	}
+}
+
+func (r *HostedRepository) Repository() (Repository, error) {
+	return buildRepository(r.Name, r.Format, "hosted", r.attributes(), nil)
+}
+
+func (r *HostedRepository) attributes() Attributes {
+	return Attributes{Storage: r.Storage, Cleanup: r.Cleanup, Maven: r.Maven, Docker: r.Docker, Yum: r.Yum, Apt: r.Apt, AptSigning: r.AptSigning}
+}
+
+func (r *ProxyRepository) Repository() (Repository, error) {
+	return buildRepository(r.Name, r.Format, "proxy", r.attributes(), nil)
+}
+
+func (r *ProxyRepository) attributes() Attributes {
+	return Attributes{
+		Storage:       r.Storage,
+		Cleanup:       r.Cleanup,
+		Proxy:         r.Proxy,
+		Httpclient:    r.Httpclient,
+		NegativeCache: r.NegativeCache,
+		Maven:         r.Maven,
+		Docker:        r.Docker,
+		DockerProxy:   r.DockerProxy,
+		Apt:           r.Apt,
+		NugetProxy:    r.NugetProxy,
+		Bower:         r.Bower,
+		RoutingRule:   r.RoutingRule,
+	}
+}
+
+func (r *GroupRepository) Repository() (Repository, error) {
+	return buildRepository(r.Name, r.Format, "group", r.attributes(), nil)
+}
+
+func (r *GroupRepository) attributes() Attributes {
+	return Attributes{Storage: r.Storage, Group: r.Group, Maven: r.Maven, Docker: r.Docker, RoutingRule: r.RoutingRule}
+}
+
+// CreateRepository creates the repository described by a spec
+func (c *Client) CreateRepository(ctx context.Context, spec RepositorySpec) error {
+	repository, err := spec.Repository()
+	if err != nil {
+		return err
+	}
+	if repository.Type == "group" {
+		if err := c.validateGroupMemberNames(ctx, repository, nil); err != nil {
+			return err
+		}
+	}
+	return c.createRepository(ctx, repository)
+}
+
+// ReplaceRepository updates an existing repository to the spec. Every attribute is replaced by the value of the spec,
+// use UpdateRepository to only change some attributes. The format and the type of the repository cannot be changed.
+// The attributes are validated like by UpdateRepository and the attributes of nexus which are not modeled by
+// Attributes keep their value
+func (c *Client) ReplaceRepository(ctx context.Context, spec RepositorySpec) error {
+	repository, err := spec.Repository()
+	if err != nil {
+		return err
+	}
+	current, err := c.getRepository(ctx, repository.Name)
+	if err != nil {
+		return err
+	}
+	if current.Recipe != repository.Recipe {
+		return newError(ErrInvalidInput, specRecipeChangedInfo, repository.Name, current.Recipe, repository.Recipe)
+	}
+	if repository.Online == nil {
+		repository.Online = current.Online
+	}
+	if err := c.validateAttributes(ctx, current, repository, nil); err != nil {
+		return err
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.updateRepository(ctx, repository); err != nil {
+		return err
+	}
+	c.logger.Printf(repoUpdatedStatus, repository.Name)
+	return nil
+}
+
+// buildRepository returns a validated repository. The attributes which do not apply to the format or the type of
+// the repository are removed and opts are applied before the validation
+func buildRepository(name, format, repoType string, attributes Attributes, opts []AttributesOption) (Repository, error) {
+	if name == "" || format == "" {
+		return Repository{}, newError(ErrInvalidInput, repoNameFormatRequiredInfo)
+	}
+	format, err := validateRepositoryFormat(format)
+	if err != nil {
+		return Repository{}, err
+	}
+	if err := validateFormatType(format, repoType); err != nil {
+		return Repository{}, err
+	}
+	for _, opt := range opts {
+		opt(&attributes)
+	}
+	var fields map[string]interface{}
+	if err := convert(attributes, &fields); err != nil {
+		return Repository{}, err
+	}
+	for key := range fields {
+		if !attributeApplies(key, format, repoType) {
+			delete(fields, key)
+		}
+	}
+	repository := Repository{Name: name, Type: repoType, Format: format, Recipe: fmt.Sprintf("%s-%s", format, repoType)}
+	if err := convert(fields, &repository.Attributes); err != nil {
+		return Repository{}, err
+	}
+	if err := validateRepository(repository); err != nil {
+		return Repository{}, err
+	}
+	if repoType == "proxy" {
+		auth := repository.Attributes.Httpclient.Authentication
+		if err := validateProxyAuthInfo(auth.Username, auth.Password); err != nil {
+			return Repository{}, err
+		}
+	}
+	return repository, nil
+}
+
+func defaultStorage() Storage {
+	return Storage{BlobStoreName: getBlobStoreName(""), StrictContentTypeValidation: true, WritePolicy: getWritePolicy(false)}
+}
+
+func defaultMaven() Maven {
+	return Maven{VersionPolicy: getVersionPolicy(false), LayoutPolicy: "STRICT"}
+}
+
+func defaultDocker() Docker {
+	return Docker{ForceBasicAuth: true, V1Enabled: false}
+}
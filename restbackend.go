@@ -0,0 +1,699 @@
+package nxrm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// restBackend manages the resources using the REST endpoints of nexus, which does not require the script API to be enabled
+type restBackend struct {
+	c *Client
+}
+
+type restSelector struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description"`
+	Expression  string `json:"expression"`
+}
+
+type restPrivilege struct {
+	Type            string   `json:"type"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	ReadOnly        bool     `json:"readOnly"`
+	Format          string   `json:"format,omitempty"`
+	Repository      string   `json:"repository,omitempty"`
+	ContentSelector string   `json:"contentSelector,omitempty"`
+	Actions         []string `json:"actions,omitempty"`
+	Pattern         string   `json:"pattern,omitempty"`
+	Domain          string   `json:"domain,omitempty"`
+	ScriptName      string   `json:"scriptName,omitempty"`
+}
+
+type restRole struct {
+	ID          string   `json:"id"`
+	Source      string   `json:"source,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Privileges  []string `json:"privileges"`
+	Roles       []string `json:"roles"`
+	ReadOnly    bool     `json:"readOnly,omitempty"`
+}
+
+var (
+	// restAttributeKeys maps the attribute keys of the script API to the keys used by the REST API
+	restAttributeKeys = map[string]string{"httpclient": "httpClient"}
+	// formatAttributes lists the attributes which only apply to a repository format
+	formatAttributes = map[string][]string{
+		"maven2": {"maven"},
+		"docker": {"docker", "dockerProxy"},
+		"yum":    {"yum"},
+		"apt":    {"apt", "aptSigning"},
+		"nuget":  {"nugetProxy"},
+		"bower":  {"bower"},
+	}
+	// typeAttributes lists the attributes which only apply to some repository types
+	typeAttributes = map[string][]string{
+		"hosted": {"yum", "aptSigning"},
+		"proxy":  {"proxy", "httpclient", "negativeCache", "dockerProxy", "nugetProxy", "bower", "routingRule"},
+		"group":  {"group", "routingRule"},
+	}
+	// dockerPortAttributes are the ports of the docker connectors, a connector is disabled when its port is not set
+	dockerPortAttributes = []string{"httpPort", "httpsPort"}
+	// restReadOnlyKeys are the keys returned by the REST API which are not accepted when a repository is updated
+	restReadOnlyKeys = []string{"format", "type", "url", "routingRuleName"}
+	// restActions maps the privilege actions of the script API to the actions of the REST API, the application
+	// privileges use the create and update actions
+	restActions = map[string]string{"create": "ADD", "update": "EDIT", "*": "ALL"}
+)
+
+func (b *restBackend) name() Backend {
+	return BackendREST
+}
+
+// doSecurity calls a security REST endpoint, which are only available from nexus 3.19
+func (b *restBackend) doSecurity(ctx context.Context, method, path string, payload, out interface{}, notFound error) error {
+	return b.c.doSecurity(ctx, method, path, payload, out, notFound)
+}
+
+// do sends the payload as json to the REST endpoint and unmarshals the response in out when out is not nil.
+// notFound is returned when nexus responds with 404 and is optional
+func (b *restBackend) do(ctx context.Context, method, path string, payload, out interface{}, notFound error) error {
+	return b.c.doREST(ctx, method, path, payload, out, notFound)
+}
+
+// doREST sends the payload as json to a REST endpoint of nexus, see restBackend.do
+func (c *Client) doREST(ctx context.Context, method, path string, payload, out interface{}, notFound error) error {
+	var requestBody RequestBody
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("%s : %w", jsonMarshalError, err)
+		}
+		requestBody.Json = data
+	}
+	url := fmt.Sprintf("%s/%s/%s", c.baseURL, apiBase, path)
+	respBody, status, err := c.doRequest(ctx, method, url, requestBody)
+	if err != nil {
+		return err
+	}
+	if status == notFoundStatus && notFound != nil {
+		return notFound
+	} else if !isSuccessStatus(status) {
+		return newAPIError(method, url, status, respBody)
+	}
+	if out != nil {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+		}
+	}
+	return nil
+}
+
+// getRepository returns the repository with its attributes.
+// Only the summary of the repository is available on versions without the REST endpoints per format
+func (b *restBackend) getRepository(ctx context.Context, name string) (Repository, error) {
+	repositories, err := b.c.getRepositories(ctx)
+	if err != nil {
+		return Repository{}, err
+	}
+	caps, err := b.c.Capabilities(ctx)
+	if err != nil {
+		return Repository{}, err
+	}
+	for _, r := range repositories {
+		if r.Name != name {
+			continue
+		}
+		if !caps.Has(FeatureRESTRepositories) {
+			r.Recipe = fmt.Sprintf("%s-%s", r.Format, r.Type)
+			return r, nil
+		}
+		var body map[string]interface{}
+		notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, name)
+		if err := b.do(ctx, "GET", restRepositoryPath(r.Format, r.Type, name), nil, &body, notFound); err != nil {
+			return Repository{}, err
+		}
+		repository, err := fromRESTRepository(body)
+		if err != nil {
+			return Repository{}, err
+		}
+		repository.URL = r.URL
+		return repository, nil
+	}
+	return Repository{}, newError(ErrRepositoryNotFound, repositoryNotFoundInfo, name)
+}
+
+func (b *restBackend) createRepository(ctx context.Context, repository Repository) error {
+	if err := b.c.requireFeature(ctx, FeatureRESTRepositories); err != nil {
+		return err
+	}
+	exists, err := b.c.repositoryExists(ctx, repository.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrRepositoryExists, repoExistsInfo, repository.Name)
+	}
+	body, err := toRESTRepository(repository)
+	if err != nil {
+		return err
+	}
+	return b.do(ctx, "POST", restRepositoryPath(repository.Format, repositoryType(repository), ""), body, nil, nil)
+}
+
+func (b *restBackend) updateGroupMembers(ctx context.Context, repository Repository) error {
+	if err := b.c.requireFeature(ctx, FeatureRESTRepositories); err != nil {
+		return err
+	}
+	current, err := b.getRepository(ctx, repository.Name)
+	if err != nil {
+		return err
+	}
+	current.Attributes.Group = repository.Attributes.Group
+	return b.updateRepository(ctx, current)
+}
+
+// updateRepository merges the attributes of a repository in the attributes returned by nexus, so that the attributes
+// which are not modeled by Attributes keep their value
+func (b *restBackend) updateRepository(ctx context.Context, repository Repository) error {
+	if err := b.c.requireFeature(ctx, FeatureRESTRepositories); err != nil {
+		return err
+	}
+	repoType := repositoryType(repository)
+	path := restRepositoryPath(repository.Format, repoType, repository.Name)
+	notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, repository.Name)
+	var body map[string]interface{}
+	if err := b.do(ctx, "GET", path, nil, &body, notFound); err != nil {
+		return err
+	}
+	attributes, err := toRESTAttributes(repository)
+	if err != nil {
+		return err
+	}
+	// the routing rule is returned as routingRuleName and set with routingRule
+	if routingRule, ok := body["routingRuleName"]; ok {
+		body["routingRule"] = routingRule
+	}
+	for _, key := range restReadOnlyKeys {
+		delete(body, key)
+	}
+	mergeAttributes(body, attributes)
+	// the REST API does not return the password, the credentials would be removed by an update without it
+	if httpClient, ok := body["httpClient"].(map[string]interface{}); ok {
+		if auth, ok := httpClient["authentication"].(map[string]interface{}); ok && isSetAttribute("username", auth["username"]) && !isSetAttribute("password", auth["password"]) {
+			return newError(ErrInvalidInput, proxyPasswordRequiredInfo, repository.Name)
+		}
+	}
+	body["name"] = repository.Name
+	body["online"] = repository.Online == nil || *repository.Online
+	setRESTFormatDefaults(body, repository.Format, repoType)
+	return b.do(ctx, "PUT", path, body, nil, notFound)
+}
+
+func (b *restBackend) deleteRepository(ctx context.Context, name string) error {
+	notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, name)
+	return b.do(ctx, "DELETE", fmt.Sprintf("%s/%s", repositoryPath, url.PathEscape(name)), nil, nil, notFound)
+}
+
+func (b *restBackend) getSelectors(ctx context.Context) ([]ContentSelector, error) {
+	var selectors []restSelector
+	if err := b.doSecurity(ctx, "GET", selectorsPath, nil, &selectors, nil); err != nil {
+		return nil, err
+	}
+	var contentSelectors []ContentSelector
+	for _, s := range selectors {
+		contentSelectors = append(contentSelectors, ContentSelector{Name: s.Name, Type: s.Type, Description: s.Description, Attributes: ContentSelectorAttributes{Expression: s.Expression}})
+	}
+	return contentSelectors, nil
+}
+
+func (b *restBackend) createSelector(ctx context.Context, selector ContentSelector) error {
+	payload := restSelector{Name: selector.Name, Description: selector.Description, Expression: selector.Attributes.Expression}
+	return b.doSecurity(ctx, "POST", selectorsPath, payload, nil, nil)
+}
+
+func (b *restBackend) updateSelector(ctx context.Context, selector ContentSelector) error {
+	payload := restSelector{Name: selector.Name, Description: selector.Description, Expression: selector.Attributes.Expression}
+	notFound := newError(ErrSelectorNotFound, selectorNotFoundInfo, selector.Name)
+	return b.doSecurity(ctx, "PUT", fmt.Sprintf("%s/%s", selectorsPath, url.PathEscape(selector.Name)), payload, nil, notFound)
+}
+
+func (b *restBackend) deleteSelector(ctx context.Context, selector ContentSelector) error {
+	notFound := newError(ErrSelectorNotFound, selectorNotFoundInfo, selector.Name)
+	return b.doSecurity(ctx, "DELETE", fmt.Sprintf("%s/%s", selectorsPath, url.PathEscape(selector.Name)), nil, nil, notFound)
+}
+
+func (b *restBackend) getPrivileges(ctx context.Context) ([]Privilege, error) {
+	var restPrivileges []restPrivilege
+	if err := b.doSecurity(ctx, "GET", privilegesPath, nil, &restPrivileges, nil); err != nil {
+		return nil, err
+	}
+	var privileges []Privilege
+	for _, p := range restPrivileges {
+		properties := PrivilegeProperties{
+			ContentSelector: p.ContentSelector,
+			Repository:      fromRESTPrivilegeRepository(p),
+			Actions:         fromRESTActions(p.Actions),
+			Pattern:         p.Pattern,
+			Domain:          p.Domain,
+			ScriptName:      p.ScriptName,
+		}
+		switch p.Type {
+		case PrivilegeRepositoryView, PrivilegeRepositoryAdmin:
+			properties.Format = p.Format
+		case PrivilegeApplication:
+			properties.Actions = strings.NewReplacer("add", "create", "edit", "update").Replace(properties.Actions)
+		}
+		privileges = append(privileges, Privilege{ID: p.Name, Name: p.Name, Description: p.Description, Type: p.Type, Properties: properties, ReadOnly: p.ReadOnly})
+	}
+	return privileges, nil
+}
+
+func (b *restBackend) createPrivilege(ctx context.Context, privilege Privilege) error {
+	payload, err := b.toRESTPrivilege(ctx, privilege)
+	if err != nil {
+		return err
+	}
+	return b.doSecurity(ctx, "POST", fmt.Sprintf("%s/%s", privilegesPath, privilege.Type), payload, nil, nil)
+}
+
+func (b *restBackend) updatePrivilege(ctx context.Context, privilege Privilege) error {
+	payload, err := b.toRESTPrivilege(ctx, privilege)
+	if err != nil {
+		return err
+	}
+	notFound := newError(ErrPrivilegeNotFound, privilegeNotFoundInfo, privilege.Name)
+	return b.doSecurity(ctx, "PUT", fmt.Sprintf("%s/%s/%s", privilegesPath, privilege.Type, url.PathEscape(privilege.Name)), payload, nil, notFound)
+}
+
+func (b *restBackend) deletePrivilege(ctx context.Context, privilege Privilege) error {
+	notFound := newError(ErrPrivilegeNotFound, privilegeNotFoundInfo, privilege.Name)
+	return b.doSecurity(ctx, "DELETE", fmt.Sprintf("%s/%s", privilegesPath, url.PathEscape(privilege.Name)), nil, nil, notFound)
+}
+
+// toRESTPrivilege converts a privilege. The REST API requires the format of the repository of the repository
+// privileges
+func (b *restBackend) toRESTPrivilege(ctx context.Context, privilege Privilege) (restPrivilege, error) {
+	p := restPrivilege{
+		Type:        privilege.Type,
+		Name:        privilege.Name,
+		Description: privilege.Description,
+		Actions:     toRESTActions(privilege.Properties.Actions),
+	}
+	switch privilege.Type {
+	case PrivilegeWildcard:
+		p.Pattern, p.Actions = privilege.Properties.Pattern, nil
+		return p, nil
+	case PrivilegeApplication:
+		p.Domain = privilege.Properties.Domain
+		return p, nil
+	case PrivilegeScript:
+		p.ScriptName = privilege.Properties.ScriptName
+		return p, nil
+	case PrivilegeRepositoryView, PrivilegeRepositoryAdmin:
+		p.Format, p.Repository = privilege.Properties.Format, privilege.Properties.Repository
+		if p.Format != "" {
+			return p, nil
+		}
+	}
+	p.Repository = privilege.Properties.Repository
+	p.ContentSelector = privilege.Properties.ContentSelector
+	switch repo := privilege.Properties.Repository; {
+	case repo == "" || repo == "*":
+		p.Format = "*"
+	case strings.HasPrefix(repo, "*-"):
+		p.Repository, p.Format = "*", strings.TrimPrefix(repo, "*-")
+	default:
+		repository, err := b.c.getRepository(ctx, repo)
+		if err != nil {
+			return p, err
+		}
+		p.Format = repository.Format
+	}
+	return p, nil
+}
+
+// fromRESTPrivilegeRepository returns the repository of a privilege as used by the script API, where the content
+// selector privileges on every repository of a format use the repository *-<format>
+func fromRESTPrivilegeRepository(p restPrivilege) string {
+	if p.Type == PrivilegeRepositoryContentSelector && p.Repository == "*" && p.Format != "" && p.Format != "*" {
+		return fmt.Sprintf("*-%s", p.Format)
+	}
+	return p.Repository
+}
+
+func (b *restBackend) getRoles(ctx context.Context) ([]Role, error) {
+	var restRoles []restRole
+	if err := b.doSecurity(ctx, "GET", rolesPath, nil, &restRoles, nil); err != nil {
+		return nil, err
+	}
+	var roles []Role
+	for _, r := range restRoles {
+		roles = append(roles, Role{RoleID: r.ID, Name: r.Name, Description: r.Description, Source: r.Source, Roles: r.Roles, Privileges: r.Privileges, ReadOnly: r.ReadOnly})
+	}
+	return roles, nil
+}
+
+// createRole creates the roles of an external source eg: RoleSourceLDAP using the extdirect API as the REST API
+// only creates nexus roles
+func (b *restBackend) createRole(ctx context.Context, role Role) error {
+	if isExternalRoleSource(role.Source) {
+		return b.c.doExtDirect(ctx, "coreui_Role", "create", toRESTRole(role), nil)
+	}
+	return b.doSecurity(ctx, "POST", rolesPath, toRESTRole(role), nil, nil)
+}
+
+func (b *restBackend) updateRole(ctx context.Context, role Role) error {
+	notFound := newError(ErrRoleNotFound, roleNotFoundInfo, role.RoleID)
+	return b.doSecurity(ctx, "PUT", fmt.Sprintf("%s/%s", rolesPath, url.PathEscape(role.RoleID)), toRESTRole(role), nil, notFound)
+}
+
+func (b *restBackend) deleteRole(ctx context.Context, id string) error {
+	notFound := newError(ErrRoleNotFound, roleNotFoundInfo, id)
+	return b.doSecurity(ctx, "DELETE", fmt.Sprintf("%s/%s", rolesPath, url.PathEscape(id)), nil, nil, notFound)
+}
+
+func toRESTRole(role Role) restRole {
+	r := restRole{ID: role.RoleID, Name: role.Name, Description: role.Description, Privileges: role.Privileges, Roles: role.Roles}
+	if isExternalRoleSource(role.Source) {
+		r.Source = role.Source
+	}
+	if r.Privileges == nil {
+		r.Privileges = []string{}
+	}
+	if r.Roles == nil {
+		r.Roles = []string{}
+	}
+	return r
+}
+
+// restRepositoryPath returns the REST path of a repository eg: v1/repositories/maven/hosted/maven-releases.
+// The name is omitted when empty
+func restRepositoryPath(format, repoType, name string) string {
+	if format == "maven2" {
+		format = "maven"
+	}
+	path := fmt.Sprintf("%s/%s/%s", repositoryPath, format, repoType)
+	if name != "" {
+		path = fmt.Sprintf("%s/%s", path, url.PathEscape(name))
+	}
+	return path
+}
+
+// repositoryType returns the type of a repository, which is derived from the recipe when not set
+func repositoryType(repository Repository) string {
+	if repository.Type != "" {
+		return repository.Type
+	}
+	if i := strings.LastIndex(repository.Recipe, "-"); i != -1 {
+		return repository.Recipe[i+1:]
+	}
+	return ""
+}
+
+// repositoryFormat returns the format of a repository, which is the recipe without the type eg: maven2 for maven2-hosted
+func repositoryFormat(repository Repository) string {
+	if repository.Format != "" {
+		return repository.Format
+	}
+	if i := strings.LastIndex(repository.Recipe, "-"); i != -1 {
+		return repository.Recipe[:i]
+	}
+	return ""
+}
+
+// toRESTRepository converts a repository to the request body of the REST API which creates it.
+// Attributes which are not set or do not apply to the format and the type of the repository are left out
+func toRESTRepository(repository Repository) (map[string]interface{}, error) {
+	attributes, err := toRESTAttributes(repository)
+	if err != nil {
+		return nil, err
+	}
+	body := cleanAttributes(attributes)
+	body["name"] = repository.Name
+	body["online"] = repository.Online == nil || *repository.Online
+	setRESTFormatDefaults(body, repository.Format, repositoryType(repository))
+	return body, nil
+}
+
+// toRESTAttributes converts the attributes of a repository to the attributes of the REST API.
+// Attributes which do not apply to the format and the type of the repository are left out
+func toRESTAttributes(repository Repository) (map[string]interface{}, error) {
+	var attributes map[string]interface{}
+	if err := convert(repository.Attributes, &attributes); err != nil {
+		return nil, err
+	}
+	repoType := repositoryType(repository)
+	result := make(map[string]interface{})
+	for key, value := range attributes {
+		if !attributeApplies(key, repository.Format, repoType) {
+			continue
+		}
+		if restKey, ok := restAttributeKeys[key]; ok {
+			key = restKey
+		}
+		result[key] = value
+	}
+	if storage, ok := result["storage"].(map[string]interface{}); ok {
+		if writePolicy, ok := storage["writePolicy"].(string); ok {
+			storage["writePolicy"] = strings.ToLower(writePolicy)
+		}
+	}
+	if cleanup, ok := result["cleanup"].(map[string]interface{}); ok {
+		policyNames, _ := cleanup["policyName"].([]interface{})
+		if policyNames == nil {
+			policyNames = []interface{}{}
+		}
+		result["cleanup"] = map[string]interface{}{"policyNames": policyNames}
+	}
+	if httpClient, ok := result["httpClient"].(map[string]interface{}); ok {
+		if auth, ok := httpClient["authentication"].(map[string]interface{}); ok {
+			// the credentials are removed when the username is not set
+			if username, _ := auth["username"].(string); username == "" {
+				httpClient["authentication"] = nil
+			} else if authType, _ := auth["type"].(string); authType == "" {
+				auth["type"] = "username"
+			}
+		}
+	}
+	return result, nil
+}
+
+// setRESTFormatDefaults sets the format specific attributes which are required by the REST API
+func setRESTFormatDefaults(body map[string]interface{}, format, repoType string) {
+	// setDefault sets the fields of an attribute which are not set, as zero values are removed from the body
+	setDefault := func(key string, value map[string]interface{}) {
+		current, ok := body[key].(map[string]interface{})
+		if !ok {
+			body[key] = value
+			return
+		}
+		for field, v := range value {
+			if _, ok := current[field]; !ok {
+				current[field] = v
+			}
+		}
+	}
+	switch {
+	case format == "nuget" && repoType == "proxy":
+		setDefault("nugetProxy", map[string]interface{}{"queryCacheItemMaxAge": 3600, "nugetVersion": "V3"})
+	case format == "bower" && repoType == "proxy":
+		setDefault("bower", map[string]interface{}{"rewritePackageUrls": true})
+	case format == "yum" && repoType == "hosted":
+		setDefault("yum", map[string]interface{}{"repodataDepth": 0, "deployPolicy": "STRICT"})
+	}
+}
+
+// fromRESTRepository converts a repository returned by the REST API
+func fromRESTRepository(body map[string]interface{}) (Repository, error) {
+	var repository Repository
+	attributes := make(map[string]interface{})
+	for key, value := range body {
+		switch key {
+		case "name", "format", "type", "url", "online":
+			continue
+		}
+		for scriptKey, restKey := range restAttributeKeys {
+			if key == restKey {
+				key = scriptKey
+			}
+		}
+		// the routing rule is set with routingRule and returned as routingRuleName
+		if key == "routingRuleName" {
+			key = "routingRule"
+		}
+		attributes[key] = value
+	}
+	if storage, ok := attributes["storage"].(map[string]interface{}); ok {
+		if writePolicy, ok := storage["writePolicy"].(string); ok {
+			storage["writePolicy"] = strings.ToUpper(writePolicy)
+		}
+	}
+	if cleanup, ok := attributes["cleanup"].(map[string]interface{}); ok {
+		attributes["cleanup"] = map[string]interface{}{"policyName": cleanup["policyNames"]}
+	}
+	if err := convert(attributes, &repository.Attributes); err != nil {
+		return repository, err
+	}
+	repository.Name, _ = body["name"].(string)
+	repository.Format, _ = body["format"].(string)
+	repository.Type, _ = body["type"].(string)
+	repository.URL, _ = body["url"].(string)
+	if online, ok := body["online"].(bool); ok {
+		repository.Online = &online
+	}
+	if repository.Format == "maven" {
+		repository.Format = "maven2"
+	}
+	repository.Recipe = fmt.Sprintf("%s-%s", repository.Format, repository.Type)
+	return repository, nil
+}
+
+// attributeApplies checks that an attribute is not specific to other formats or other repository types
+func attributeApplies(key, format, repoType string) bool {
+	return appliesTo(formatAttributes, key, format) && appliesTo(typeAttributes, key, repoType)
+}
+
+// appliesTo checks that an attribute is either not listed in attributes or listed for value
+func appliesTo(attributes map[string][]string, key, value string) bool {
+	listed := false
+	for v, keys := range attributes {
+		if entryExists(keys, key) {
+			if v == value {
+				return true
+			}
+			listed = true
+		}
+	}
+	return !listed
+}
+
+// cleanAttributes removes the attributes which are not set so that nexus applies its defaults.
+// Boolean values are always kept
+func cleanAttributes(attributes map[string]interface{}) map[string]interface{} {
+	cleaned := make(map[string]interface{})
+	for key, value := range attributes {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			if v == "" {
+				continue
+			}
+		case float64:
+			if v == 0 {
+				continue
+			}
+		case []interface{}:
+			if len(v) == 0 {
+				continue
+			}
+		case map[string]interface{}:
+			v = cleanAttributes(v)
+			if len(v) == 0 {
+				continue
+			}
+			value = v
+		}
+		cleaned[key] = value
+	}
+	return cleaned
+}
+
+// mergeAttributes sets the attributes of patch in stored, the attributes of stored which are not in patch keep their
+// value. The attributes which are not set in patch are removed from stored, except the password which nexus does
+// not return
+func mergeAttributes(stored, patch map[string]interface{}) {
+	for key, value := range patch {
+		if p, ok := value.(map[string]interface{}); ok {
+			s, ok := stored[key].(map[string]interface{})
+			if !ok {
+				s = make(map[string]interface{})
+			}
+			mergeAttributes(s, p)
+			if len(s) == 0 {
+				delete(stored, key)
+			} else {
+				stored[key] = s
+			}
+			continue
+		}
+		if !isSetAttribute(key, value) {
+			if key != "password" {
+				delete(stored, key)
+			}
+			continue
+		}
+		stored[key] = value
+	}
+}
+
+// isSetAttribute reports whether an attribute is set. Booleans and numbers are set, except the docker ports for
+// which 0 means that the connector is disabled
+func isSetAttribute(key string, value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case float64:
+		return v != 0 || !entryExists(dockerPortAttributes, key)
+	case []interface{}:
+		return len(v) > 0
+	}
+	return true
+}
+
+// toRESTActions converts comma separated actions eg: browse,read to the actions of the REST API eg: [BROWSE READ]
+func toRESTActions(actions string) []string {
+	var restActionList []string
+	for _, action := range strings.Split(actions, ",") {
+		action = strings.TrimSpace(action)
+		if action == "" {
+			continue
+		}
+		restAction, ok := restActions[action]
+		if !ok {
+			restAction = strings.ToUpper(action)
+		}
+		if !entryExists(restActionList, restAction) {
+			restActionList = append(restActionList, restAction)
+		}
+	}
+	return restActionList
+}
+
+// fromRESTActions converts the actions of the REST API to comma separated actions
+func fromRESTActions(actions []string) string {
+	var actionList []string
+	for _, action := range actions {
+		if action == "ALL" {
+			return "*"
+		}
+		actionList = append(actionList, strings.ToLower(action))
+	}
+	return strings.Join(actionList, ",")
+}
+
+// convert copies in to out using their json representation
+func convert(in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	return nil
+}
+
+// isSuccessStatus checks if a status is a 2xx status
+func isSuccessStatus(status string) bool {
+	code := statusCode(status)
+	return code >= 200 && code < 300
+}
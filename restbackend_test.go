@@ -0,0 +1,90 @@
+package nxrm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepositoryFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository Repository
+		want       string
+	}{
+		{"format", Repository{Format: "maven2", Recipe: "npm-hosted"}, "maven2"},
+		{"recipe", Repository{Recipe: "maven2-proxy"}, "maven2"},
+		{"recipe containing another format", Repository{Recipe: "pypi-group"}, "pypi"},
+		{"unknown", Repository{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := repositoryFormat(tt.repository); got != tt.want {
+				t.Errorf("repositoryFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanAttributes(t *testing.T) {
+	attributes := map[string]interface{}{
+		"storage":       map[string]interface{}{"blobStoreName": "", "strictContentTypeValidation": false},
+		"negativeCache": map[string]interface{}{"enabled": true, "timeToLive": float64(0)},
+		"group":         map[string]interface{}{"memberNames": []interface{}{}},
+	}
+	want := map[string]interface{}{
+		"storage":       map[string]interface{}{"strictContentTypeValidation": false},
+		"negativeCache": map[string]interface{}{"enabled": true},
+	}
+	if got := cleanAttributes(attributes); !reflect.DeepEqual(got, want) {
+		t.Errorf("cleanAttributes() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeAttributes(t *testing.T) {
+	stored := map[string]interface{}{
+		"storage":       map[string]interface{}{"blobStoreName": "default", "dataStoreName": "nexus"},
+		"negativeCache": map[string]interface{}{"enabled": true, "timeToLive": float64(1440)},
+		"docker":        map[string]interface{}{"httpPort": float64(8082), "subdomain": "docker"},
+		"httpClient": map[string]interface{}{
+			"connection":     map[string]interface{}{"timeout": float64(60)},
+			"authentication": map[string]interface{}{"type": "username", "username": "user"},
+		},
+		"cleanup":     map[string]interface{}{"policyNames": []interface{}{"daily"}},
+		"routingRule": "blocked",
+	}
+	patch := map[string]interface{}{
+		"storage":       map[string]interface{}{"blobStoreName": "default", "strictContentTypeValidation": false},
+		"negativeCache": map[string]interface{}{"enabled": true, "timeToLive": float64(0)},
+		"docker":        map[string]interface{}{"httpPort": float64(0), "httpsPort": float64(8443)},
+		"httpClient": map[string]interface{}{
+			"authentication": map[string]interface{}{"type": "username", "username": "other", "password": ""},
+		},
+		"cleanup":     map[string]interface{}{"policyNames": []interface{}{}},
+		"routingRule": "",
+	}
+	want := map[string]interface{}{
+		"storage":       map[string]interface{}{"blobStoreName": "default", "dataStoreName": "nexus", "strictContentTypeValidation": false},
+		"negativeCache": map[string]interface{}{"enabled": true, "timeToLive": float64(0)},
+		"docker":        map[string]interface{}{"httpsPort": float64(8443), "subdomain": "docker"},
+		"httpClient": map[string]interface{}{
+			"connection":     map[string]interface{}{"timeout": float64(60)},
+			"authentication": map[string]interface{}{"type": "username", "username": "other"},
+		},
+	}
+	mergeAttributes(stored, patch)
+	if !reflect.DeepEqual(stored, want) {
+		t.Errorf("mergeAttributes() = %v, want %v", stored, want)
+	}
+}
+
+func TestToRESTAttributesRemovesCredentials(t *testing.T) {
+	repository := Repository{Format: "raw", Type: "proxy", Attributes: Attributes{Httpclient: HttpClient{Authentication: HttpClientAuth{Type: "username"}}}}
+	attributes, err := toRESTAttributes(repository)
+	if err != nil {
+		t.Fatalf("toRESTAttributes() error = %v", err)
+	}
+	httpClient, _ := attributes["httpClient"].(map[string]interface{})
+	if auth, ok := httpClient["authentication"]; !ok || auth != nil {
+		t.Errorf("toRESTAttributes() authentication = %v, want nil", auth)
+	}
+}
@@ -0,0 +1,145 @@
+package nxrm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures the retries of the requests which failed with a transient error.
+// Idempotent requests are retried on connection errors and on the retryable statuses.
+// Non idempotent requests are only retried when the connection could not be established,
+// except for the scripts which only read data from nexus
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one. 1 or less disables the retries
+	MaxAttempts int
+	// InitialBackoff is the wait time before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait time between two attempts
+	MaxBackoff time.Duration
+	// Multiplier is applied to the wait time after every attempt
+	Multiplier float64
+	// Jitter randomizes the wait time by up to the given fraction eg: 0.2 for +/- 20%
+	Jitter float64
+	// RetryableStatuses are the status codes which are retried
+	RetryableStatuses []int
+	// RespectRetryAfter waits for the duration of the Retry-After header instead of the backoff when present,
+	// up to MaxBackoff
+	RespectRetryAfter bool
+}
+
+var (
+	// DefaultRetryPolicy is used unless a policy is set using WithRetryPolicy
+	DefaultRetryPolicy = RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    500 * time.Millisecond,
+		MaxBackoff:        10 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		RetryableStatuses: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RespectRetryAfter: true,
+	}
+	// NoRetry disables the retries
+	NoRetry = RetryPolicy{MaxAttempts: 1}
+)
+
+// retrySafeKey marks a context of a non idempotent request which can safely be retried
+type retrySafeKey struct{}
+
+// withRetrySafe marks the requests made with the returned context as safe to retry
+func withRetrySafe(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retrySafeKey{}, true)
+}
+
+func isRetrySafe(ctx context.Context) bool {
+	safe, _ := ctx.Value(retrySafeKey{}).(bool)
+	return safe
+}
+
+// backoff returns the wait time after an attempt, attempts start at 1
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
+
+// wait returns the wait time after an attempt. The duration of the Retry-After header is capped by MaxBackoff,
+// so that a server cannot stall the requests
+func (p RetryPolicy) wait(attempt int, header http.Header) time.Duration {
+	wait := p.backoff(attempt)
+	if d, ok := retryAfter(header); ok && p.RespectRetryAfter {
+		wait = d
+		if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+			wait = p.MaxBackoff
+		}
+	}
+	return wait
+}
+
+func (p RetryPolicy) retryableStatus(code int) bool {
+	for _, s := range p.RetryableStatuses {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which is either a number of seconds or a http date
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isConnectionError checks if the connection to the server could not be established, in which case the request was not sent
+func isConnectionError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// sleep waits for the duration or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package nxrm
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{"first attempt", policy, 1, 100 * time.Millisecond},
+		{"multiplied", policy, 3, 400 * time.Millisecond},
+		{"capped", policy, 10, time.Second},
+		{"multiplier below 1", RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 0.5}, 3, 100 * time.Millisecond},
+		{"no max backoff", RetryPolicy{InitialBackoff: time.Second, Multiplier: 2}, 5, 16 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.backoff(tt.attempt); got != tt.want {
+				t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, Multiplier: 2, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 800*time.Millisecond || got > 1200*time.Millisecond {
+			t.Fatalf("backoff(1) = %v, want between 800ms and 1.2s", got)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"missing", "", 0, false},
+		{"seconds", "120", 2 * time.Minute, true},
+		{"negative seconds", "-1", 0, false},
+		{"past date", "Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+		{"invalid", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.value != "" {
+				header.Set("Retry-After", tt.value)
+			}
+			got, ok := retryAfter(header)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryAfterFutureDate(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	got, ok := retryAfter(header)
+	if !ok || got <= 58*time.Minute || got > time.Hour {
+		t.Errorf("retryAfter() = %v, %v, want about 1h, true", got, ok)
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodHead, true},
+		{http.MethodOptions, true},
+		{http.MethodPut, true},
+		{http.MethodDelete, true},
+		{http.MethodPost, false},
+		{http.MethodPatch, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := isIdempotent(tt.method); got != tt.want {
+				t.Errorf("isIdempotent(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 10 * time.Second, Multiplier: 2, RespectRetryAfter: true}
+	tests := []struct {
+		name       string
+		policy     RetryPolicy
+		retryAfter string
+		want       time.Duration
+	}{
+		{"backoff", policy, "", 100 * time.Millisecond},
+		{"retry after", policy, "5", 5 * time.Second},
+		{"retry after capped", policy, "3600", 10 * time.Second},
+		{"retry after ignored", RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 2}, "5", 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+			if got := tt.policy.wait(1, header); got != tt.want {
+				t.Errorf("wait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,8 @@
 package nxrm
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
-	"os"
 	"strings"
 )
 
@@ -18,9 +16,14 @@ type Role struct {
 	ReadOnly    bool     `json:"readOnly"`
 }
 
-func ListRoles(id string) {
+// ListRoles prints the details of a role when an id is provided,
+// otherwise prints the ids of all the roles
+func (c *Client) ListRoles(ctx context.Context, id string) error {
 	if id != "" {
-		role := getRole(id)
+		role, err := c.GetRole(ctx, id)
+		if err != nil {
+			return err
+		}
 		fmt.Printf("Role Details:\n"+
 			"ID: %s\n"+
 			"Name: %s\n"+
@@ -29,65 +32,111 @@ func ListRoles(id string) {
 			"Roles: %s\n"+
 			"Privileges: %s\n",
 			role.RoleID, role.Name, role.Description, role.Source, role.Roles, role.Privileges)
-	} else {
-		rIds := getRoleIDs()
-		printStringSlice(rIds)
-		log.Printf("Number of roles in nexus : %d\n", len(rIds))
+		return nil
+	}
+	roles, err := c.GetRoles(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, r := range roles {
+		fmt.Println(r.RoleID)
 	}
+	fmt.Printf("Number of roles in nexus : %d\n", len(roles))
+	return nil
 }
 
-func CreateRole(id, description, roleMembers, rolePrivileges string) {
-	if id == "" {
-		log.Printf("%s : %s", getfuncName(), createRoleRequiredInfo)
-		os.Exit(1)
-	}
-	if !roleExists(id) {
-		validRoleMembers := validateRoleMembers(id, roleMembers)
-		validRolePrivileges := validateRolePrivileges(rolePrivileges)
-		if len(validRoleMembers)+len(validRolePrivileges) < 1 {
-			log.Printf("%s : You are creating a role without any valid role member or role privilege", getfuncName())
-		}
-		role := Role{RoleID: id, Name: id, Description: getRoleDesc(description), Source: getRoleSource(), Roles: validRoleMembers, Privileges: validRolePrivileges}
-		payload, err := json.Marshal(role)
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript(createRoleScript, string(payload))
-		if result.Status == successStatus {
-			log.Printf(createRoleSuccessInfo, id, validRoleMembers, validRolePrivileges)
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
+// GetRoles returns the roles matching the options
+func (c *Client) GetRoles(ctx context.Context, opts ListOptions) ([]Role, error) {
+	if err := opts.validate(ResourceRole); err != nil {
+		return nil, err
+	}
+	roles, err := c.getRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Role
+	for _, r := range roles {
+		if opts.match(r.RoleID, r.Source, "") {
+			result = append(result, r)
 		}
-	} else {
-		log.Printf(roleExistsInfo, id)
 	}
+	return result, nil
+}
+
+// GetRole returns a role
+func (c *Client) GetRole(ctx context.Context, id string) (Role, error) {
+	return c.getRole(ctx, id)
+}
+
+func (c *Client) CreateRole(ctx context.Context, id, description, roleMembers, rolePrivileges string) error {
+	return c.createRole(ctx, id, getRoleSource(), description, roleMembers, rolePrivileges)
+}
+
+func (c *Client) createRole(ctx context.Context, id, source, description, roleMembers, rolePrivileges string) error {
+	if id == "" {
+		return newError(ErrInvalidInput, createRoleRequiredInfo)
+	}
+	exists, err := c.roleExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrRoleExists, roleExistsInfo, id)
+	}
+	validRoleMembers, err := c.validateRoleMembers(ctx, id, roleMembers)
+	if err != nil {
+		return err
+	}
+	validRolePrivileges, err := c.validateRolePrivileges(ctx, rolePrivileges)
+	if err != nil {
+		return err
+	}
+	if len(validRoleMembers)+len(validRolePrivileges) < 1 {
+		c.logger.Printf("%s : You are creating a role without any valid role member or role privilege", id)
+	}
+	role := Role{RoleID: id, Name: id, Description: getRoleDesc(description), Source: source, Roles: validRoleMembers, Privileges: validRolePrivileges}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.createRole(ctx, role); err != nil {
+		return err
+	}
+	c.logger.Printf(createRoleSuccessInfo, id, validRoleMembers, validRolePrivileges)
+	return nil
 }
 
-func UpdateRole(id, description, roleMembers, rolePrivileges, updateAction string) {
+func (c *Client) UpdateRole(ctx context.Context, id, description, roleMembers, rolePrivileges, updateAction string) error {
 	if id == "" {
-		log.Printf("%s : %s", getfuncName(), roleIDRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, roleIDRequiredInfo)
 	}
 
 	if updateAction == "" {
-		log.Printf("%s : %s", getfuncName(), fmt.Sprintf(UpdateActionRequiredInfo, UpdateActions))
-		os.Exit(1)
+		return newError(ErrInvalidInput, UpdateActionRequiredInfo, UpdateActions)
+	} else if !entryExists(UpdateActions, updateAction) {
+		return newError(ErrInvalidInput, UpdateActionInvalidInfo, updateAction, UpdateActions)
 	}
 
-	role := getRole(id)
-
-	if role.RoleID == "" {
-		os.Exit(1)
+	role, err := c.getRole(ctx, id)
+	if err != nil {
+		return err
 	}
 
 	if description != "" {
 		role.Description = description
 	}
 
-	validRoleMembers := validateRoleMembers(id, roleMembers)
-	validRolePrivileges := validateRolePrivileges(rolePrivileges)
+	validRoleMembers, err := c.validateRoleMembers(ctx, id, roleMembers)
+	if err != nil {
+		return err
+	}
+	validRolePrivileges, err := c.validateRolePrivileges(ctx, rolePrivileges)
+	if err != nil {
+		return err
+	}
 
 	if len(validRoleMembers)+len(validRolePrivileges) < 1 {
-		log.Printf(roleItemsRequiredInfo, getfuncName())
-		os.Exit(1)
+		return newError(ErrInvalidInput, roleItemsRequiredInfo, id)
 	}
 
 	if updateAction == "add" {
@@ -101,118 +150,110 @@ func UpdateRole(id, description, roleMembers, rolePrivileges, updateAction strin
 				role.Privileges = append(role.Privileges, rp)
 			}
 		}
-	} else if updateAction == "remove" {
+	} else {
 		for _, rm := range validRoleMembers {
-			if entryExists(role.Roles, rm) {
-				role.Roles = removeEntryFromSlice(role.Roles, rm)
-			}
+			role.Roles = removeEntryFromSlice(role.Roles, rm)
 		}
 		for _, rp := range validRolePrivileges {
-			if entryExists(role.Privileges, rp) {
-				role.Privileges = removeEntryFromSlice(role.Privileges, rp)
-			}
+			role.Privileges = removeEntryFromSlice(role.Privileges, rp)
 		}
-	} else {
-		log.Printf("%s : %s", getfuncName(), fmt.Sprintf(UpdateActionInvalidInfo, updateAction, UpdateActions))
-		os.Exit(1)
 	}
 
 	if len(role.Roles)+len(role.Privileges) < 1 {
-		log.Printf(roleItemsRequiredInfo, getfuncName())
-		os.Exit(1)
+		return newError(ErrInvalidInput, roleItemsRequiredInfo, id)
 	}
 
-	if roleExists(id) {
-		if roleExists(id) {
-			payload, err := json.Marshal(Role{RoleID: id})
-			logJsonMarshalError(err, jsonMarshalError)
-			result := RunScript(deleteRoleScript, string(payload))
-			if result.Status != successStatus {
-				log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			}
-		} else {
-			log.Printf(roleNotFoundInfo, id)
-		}
-		if !roleExists(id) {
-			role := Role{RoleID: id, Name: id, Description: role.Description, Source: getRoleSource(), Roles: role.Roles, Privileges: role.Privileges}
-			payload, err := json.Marshal(role)
-			logJsonMarshalError(err, jsonMarshalError)
-			result := RunScript(createRoleScript, string(payload))
-			if result.Status != successStatus {
-				log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			}
-		}
-		log.Printf(updateRoleSuccessInfo, id)
-	} else {
-		log.Printf(roleNotFoundInfo, id)
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	source := getRoleSource()
+	if isExternalRoleSource(role.Source) {
+		source = role.Source
+	}
+	role = Role{RoleID: id, Name: id, Description: role.Description, Source: source, Roles: role.Roles, Privileges: role.Privileges}
+	if err := b.updateRole(ctx, role); err != nil {
+		return err
 	}
+	c.logger.Printf(updateRoleSuccessInfo, id)
+	return nil
 }
 
-func CreateOrUpdateRole(id, description, roleMembers, rolePrivileges, updateAction string) {
-	if !roleExists(id) {
-		CreateRole(id, description, roleMembers, rolePrivileges)
-	} else {
-		UpdateRole(id, description, roleMembers, rolePrivileges, updateAction)
+func (c *Client) CreateOrUpdateRole(ctx context.Context, id, description, roleMembers, rolePrivileges, updateAction string) error {
+	exists, err := c.roleExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return c.CreateRole(ctx, id, description, roleMembers, rolePrivileges)
 	}
+	return c.UpdateRole(ctx, id, description, roleMembers, rolePrivileges, updateAction)
 }
 
-func DeleteRole(id string) {
+func (c *Client) DeleteRole(ctx context.Context, id string) error {
 	if id == "" {
-		log.Printf("%s : %s", getfuncName(), roleIDRequiredInfo)
-		os.Exit(1)
-	}
-	if roleExists(id) {
-		payload, err := json.Marshal(Role{RoleID: id})
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript(deleteRoleScript, string(payload))
-		if result.Status == successStatus {
-			log.Printf(deleteRoleSuccessInfo, id)
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-		}
-	} else {
-		log.Printf(roleNotFoundInfo, id)
+		return newError(ErrInvalidInput, roleIDRequiredInfo)
 	}
+	exists, err := c.roleExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrRoleNotFound, roleNotFoundInfo, id)
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.deleteRole(ctx, id); err != nil {
+		return err
+	}
+	c.logger.Printf(deleteRoleSuccessInfo, id)
+	return nil
 }
 
-func getRoles() []Role {
-	payload, err := json.Marshal(Role{})
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript(getRoleScript, string(payload))
-	return result.Roles
+func (c *Client) getRoles(ctx context.Context) ([]Role, error) {
+	b, err := c.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.getRoles(ctx)
 }
 
-func getRole(id string) Role {
-	var role Role
-	roles := getRoles()
+func (c *Client) getRole(ctx context.Context, id string) (Role, error) {
+	if id == "" {
+		return Role{}, newError(ErrInvalidInput, roleIDRequiredInfo)
+	}
+	roles, err := c.getRoles(ctx)
+	if err != nil {
+		return Role{}, err
+	}
 	for _, r := range roles {
 		if r.RoleID == id {
-			role = r
+			return r, nil
 		}
 	}
-	if role.RoleID == "" {
-		log.Printf(roleNotFoundInfo, id)
-	}
-	return role
+	return Role{}, newError(ErrRoleNotFound, roleNotFoundInfo, id)
 }
 
-func getRoleIDs() []string {
+func (c *Client) getRoleIDs(ctx context.Context) ([]string, error) {
 	var rIDs []string
-	roles := getRoles()
+	roles, err := c.getRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
 	for _, r := range roles {
 		rIDs = append(rIDs, r.RoleID)
 	}
-	return rIDs
+	return rIDs, nil
 }
 
-func roleExists(id string) bool {
-	roles := getRoles()
-	for _, r := range roles {
-		if r.RoleID == id {
-			return true
-		}
+func (c *Client) roleExists(ctx context.Context, id string) (bool, error) {
+	rIDs, err := c.getRoleIDs(ctx)
+	if err != nil {
+		return false, err
 	}
-	return false
+	return entryExists(rIDs, id), nil
 }
 
 func getRoleDesc(description string) string {
@@ -226,50 +267,63 @@ func getRoleSource() string {
 	return defaultRoleSource
 }
 
-func validateRoleMembers(id, roleMembers string) []string {
+// isExternalRoleSource reports whether the roles of a source map the groups of an external source
+func isExternalRoleSource(source string) bool {
+	return source != "" && source != defaultRoleSource && source != UserSourceDefault
+}
+
+func (c *Client) validateRoleMembers(ctx context.Context, id, roleMembers string) ([]string, error) {
 	var validList []string
+	if roleMembers == "" {
+		c.logger.Printf("%s\n", noRoleMemberProvidedInfo)
+		return validList, nil
+	}
+	rIDs, err := c.getRoleIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 	roleMembersList := strings.Split(strings.Replace(roleMembers, " ", "", -1), ",")
-	if len(roleMembersList) >= 1 && roleMembers != "" {
-		for _, rm := range roleMembersList {
-			if roleExists(rm) {
-				if id != rm {
-					validList = append(validList, rm)
-				} else {
-					log.Printf(cannotBeSameRoleInfo, rm, id)
-				}
-			} else {
-				log.Printf(roleMemberNotFoundInfo, rm)
-			}
-		}
-		if len(validList) < 1 {
-			log.Println(noValidRoleMemberInfo)
+	for _, rm := range roleMembersList {
+		if !entryExists(rIDs, rm) {
+			c.logger.Printf(roleMemberNotFoundInfo, rm)
+		} else if id == rm {
+			c.logger.Printf(cannotBeSameRoleInfo, rm, id)
+		} else {
+			validList = append(validList, rm)
 		}
-	} else {
-		log.Println(noRoleMemberProvidedInfo)
 	}
-	return validList
+	if len(validList) < 1 {
+		c.logger.Printf("%s\n", noValidRoleMemberInfo)
+	}
+	return validList, nil
 }
 
-func validateRolePrivileges(rolePrivileges string) []string {
+func (c *Client) validateRolePrivileges(ctx context.Context, rolePrivileges string) ([]string, error) {
 	var validList []string
+	if rolePrivileges == "" {
+		c.logger.Printf("%s\n", noRolePrivilegesIProvidedInfo)
+		return validList, nil
+	}
+	privileges, err := c.getPrivileges(ctx)
+	if err != nil {
+		return nil, err
+	}
 	rolePrivilegesList := strings.Split(strings.Replace(rolePrivileges, " ", "", -1), ",")
-	if len(rolePrivilegesList) >= 1 && rolePrivileges != "" {
-		for _, rp := range rolePrivilegesList {
-			if privilegeExists(rp) {
-				validList = append(validList, getPrivilegeID(rp))
-			} else {
-				if Debug {
-					log.Printf(rolePrivilegeNotFoundInfo, rp)
-				}
+	for _, rp := range rolePrivilegesList {
+		found := false
+		for _, p := range privileges {
+			if p.Name == rp {
+				validList = append(validList, p.ID)
+				found = true
+				break
 			}
 		}
-		if len(validList) < 1 {
-			if Debug {
-				log.Println(noValidRolePrivilegeInfo)
-			}
+		if !found && c.debug {
+			c.logger.Printf(rolePrivilegeNotFoundInfo, rp)
 		}
-	} else {
-		log.Println(noRolePrivilegesIProvidedInfo)
 	}
-	return validList
+	if len(validList) < 1 && c.debug {
+		c.logger.Printf("%s\n", noValidRolePrivilegeInfo)
+	}
+	return validList, nil
 }
@@ -0,0 +1,225 @@
+package nxrm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	RoutingRuleAllow = "ALLOW"
+	RoutingRuleBlock = "BLOCK"
+
+	ResourceRoutingRule = "routing-rule"
+)
+
+// RoutingRule allows or blocks the requests of proxy and group repositories whose path matches one of the matchers.
+// The repositories reference the rule by name using the RoutingRule attribute, eg: to block internal package names
+// from being fetched from a public proxy
+type RoutingRule struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Mode is either RoutingRuleAllow, only the matching requests are allowed, or RoutingRuleBlock
+	Mode string `json:"mode"`
+	// Matchers are regular expressions matched against the path of the requests eg: ^/com/example/.*
+	Matchers []string `json:"matchers"`
+}
+
+// ListRoutingRules prints the details of a routing rule when a name is provided,
+// otherwise prints the names of all the routing rules
+func (c *Client) ListRoutingRules(ctx context.Context, name string) error {
+	if name != "" {
+		rule, err := c.GetRoutingRule(ctx, name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Name: %s\nDescription: %s\nMode: %s\nMatchers: %s\n", rule.Name, rule.Description, rule.Mode, strings.Join(rule.Matchers, ", "))
+		return nil
+	}
+	rules, err := c.GetRoutingRules(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		fmt.Println(r.Name)
+	}
+	fmt.Printf("Number of routing rules : %d\n", len(rules))
+	return nil
+}
+
+// GetRoutingRules returns the routing rules matching the options, the type is matched against the mode
+func (c *Client) GetRoutingRules(ctx context.Context, opts ListOptions) ([]RoutingRule, error) {
+	if err := opts.validate(ResourceRoutingRule); err != nil {
+		return nil, err
+	}
+	rules, err := c.getRoutingRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []RoutingRule
+	for _, r := range rules {
+		if opts.match(r.Name, r.Mode, "") {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// GetRoutingRule returns a routing rule
+func (c *Client) GetRoutingRule(ctx context.Context, name string) (RoutingRule, error) {
+	if name == "" {
+		return RoutingRule{}, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	var rule RoutingRule
+	notFound := newError(ErrRoutingRuleNotFound, routingRuleNotFoundInfo, name)
+	if err := c.doREST(ctx, "GET", fmt.Sprintf("%s/%s", routingRulesPath, url.PathEscape(name)), nil, &rule, notFound); err != nil {
+		return RoutingRule{}, err
+	}
+	return rule, nil
+}
+
+// CreateRoutingRule creates a routing rule
+func (c *Client) CreateRoutingRule(ctx context.Context, rule RoutingRule) error {
+	if err := validateRoutingRule(rule); err != nil {
+		return err
+	}
+	exists, err := c.routingRuleExists(ctx, rule.Name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrRoutingRuleExists, routingRuleExistsInfo, rule.Name)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceRoutingRule, Name: rule.Name, Action: ActionCreate, After: rule})
+	} else if err := c.doREST(ctx, "POST", routingRulesPath, rule, nil, nil); err != nil {
+		return err
+	}
+	c.logger.Printf(routingRuleCreatedInfo, rule.Name)
+	return nil
+}
+
+// UpdateRoutingRule replaces the description, the mode and the matchers of a routing rule
+func (c *Client) UpdateRoutingRule(ctx context.Context, rule RoutingRule) error {
+	if err := validateRoutingRule(rule); err != nil {
+		return err
+	}
+	before, err := c.GetRoutingRule(ctx, rule.Name)
+	if err != nil {
+		return err
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceRoutingRule, Name: rule.Name, Action: ActionUpdate, Before: before, After: rule})
+	} else {
+		notFound := newError(ErrRoutingRuleNotFound, routingRuleNotFoundInfo, rule.Name)
+		if err := c.doREST(ctx, "PUT", fmt.Sprintf("%s/%s", routingRulesPath, url.PathEscape(rule.Name)), rule, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(routingRuleUpdatedInfo, rule.Name)
+	return nil
+}
+
+// DeleteRoutingRule deletes a routing rule. Rules assigned to repositories are not deleted
+func (c *Client) DeleteRoutingRule(ctx context.Context, name string) error {
+	before, err := c.GetRoutingRule(ctx, name)
+	if err != nil {
+		return err
+	}
+	repositories, err := c.getRepositoriesWithAttributes(ctx)
+	if err != nil {
+		return err
+	}
+	var used []string
+	for _, r := range repositories {
+		if r.Attributes.RoutingRule == name {
+			used = append(used, r.Name)
+		}
+	}
+	if len(used) > 0 {
+		return newError(ErrInvalidInput, routingRuleInUseInfo, name, used)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceRoutingRule, Name: name, Action: ActionDelete, Before: before})
+	} else {
+		notFound := newError(ErrRoutingRuleNotFound, routingRuleNotFoundInfo, name)
+		if err := c.doREST(ctx, "DELETE", fmt.Sprintf("%s/%s", routingRulesPath, url.PathEscape(name)), nil, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(routingRuleDeletedInfo, name)
+	return nil
+}
+
+// SetRoutingRule assigns a routing rule to a proxy or group repository, replacing its current rule
+func (c *Client) SetRoutingRule(ctx context.Context, repoName, ruleName string) error {
+	if repoName == "" || ruleName == "" {
+		return newError(ErrInvalidInput, setRoutingRuleRequiredInfo)
+	}
+	repository, err := c.getRepository(ctx, repoName)
+	if err != nil {
+		return err
+	}
+	if repositoryType(repository) == "hosted" {
+		return newError(ErrInvalidInput, routingRuleHostedRepoInfo, repoName)
+	}
+	return c.UpdateRepository(ctx, repoName, func(attributes *Attributes) {
+		attributes.RoutingRule = ruleName
+	})
+}
+
+// RemoveRoutingRule removes the routing rule of a repository
+func (c *Client) RemoveRoutingRule(ctx context.Context, repoName string) error {
+	return c.UpdateRepository(ctx, repoName, func(attributes *Attributes) {
+		attributes.RoutingRule = ""
+	})
+}
+
+func (c *Client) getRoutingRules(ctx context.Context) ([]RoutingRule, error) {
+	var rules []RoutingRule
+	notSupported := newError(ErrNotSupported, routingRulesNotSupportedInfo)
+	if err := c.doREST(ctx, "GET", routingRulesPath, nil, &rules, notSupported); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (c *Client) routingRuleExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.GetRoutingRule(ctx, name)
+	if errors.Is(err, ErrRoutingRuleNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// validateRepositoryRoutingRule checks that the routing rule of a repository exists, an empty name is not checked
+func (c *Client) validateRepositoryRoutingRule(ctx context.Context, name string) error {
+	if name == "" {
+		return nil
+	}
+	_, err := c.GetRoutingRule(ctx, name)
+	return err
+}
+
+func validateRoutingRule(rule RoutingRule) error {
+	if rule.Name == "" {
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	if !entryExists(RoutingRuleModes, rule.Mode) {
+		return newError(ErrInvalidInput, routingRuleModeNotValidInfo, rule.Mode, RoutingRuleModes)
+	}
+	if len(rule.Matchers) < 1 {
+		return newError(ErrInvalidInput, routingRuleMatcherRequiredInfo)
+	}
+	for _, matcher := range rule.Matchers {
+		if _, err := regexp.Compile(matcher); err != nil {
+			return newError(ErrInvalidInput, routingRuleMatcherNotValidInfo, matcher, err)
+		}
+	}
+	return nil
+}
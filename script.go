@@ -1,13 +1,19 @@
 package nxrm
 
 import (
+	"context"
+	"embed"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
+	"path/filepath"
 	"sort"
 )
 
+// embeddedScripts contains the groovy scripts listed in NexusScripts
+//
+//go:embed scripts/groovy/*.groovy
+var embeddedScripts embed.FS
+
 type Script struct {
 	Name    string `json:"name"`
 	Type    string `json:"type"`
@@ -26,6 +32,7 @@ type ScriptResult struct {
 	Type             string            `json:"type"`
 	Format           string            `json:"format"`
 	Recipe           string            `json:"recipe"`
+	Online           *bool             `json:"online"`
 	Message          string            `json:"message"`
 	Attributes       Attributes        `json:"attributes"`
 	ContentSelectors []ContentSelector `json:"contentSelectors"`
@@ -33,203 +40,292 @@ type ScriptResult struct {
 	Roles            []Role            `json:"roles"`
 }
 
-func ListScripts(name string) {
+// ListScripts prints a script when a name is provided,
+// otherwise prints the names of all the scripts available in nexus
+func (c *Client) ListScripts(ctx context.Context, name string) error {
 	if name != "" {
-		script := getScript(name)
+		script, err := c.GetScript(ctx, name)
+		if err != nil {
+			return err
+		}
 		fmt.Println(script)
+		return nil
+	}
+	scripts, err := c.GetScripts(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(scripts, func(i, j int) bool { return scripts[i].Name < scripts[j].Name })
+	for _, s := range scripts {
+		fmt.Println(s.Name)
+	}
+	if len(scripts) == 0 {
+		fmt.Println("There are no scripts available in nexus")
 	} else {
-		scriptsList := getScripts()
-		sort.Strings(scriptsList)
-		printStringSlice(scriptsList)
-		if len(scriptsList) == 0 {
-			fmt.Println("There are no scripts available in nexus")
-		} else {
-			fmt.Printf("No of scripts in nexus : %d\n", len(scriptsList))
+		fmt.Printf("No of scripts in nexus : %d\n", len(scripts))
+	}
+	return nil
+}
+
+// GetScripts returns the scripts available in nexus matching the options
+func (c *Client) GetScripts(ctx context.Context, opts ListOptions) ([]Script, error) {
+	if err := opts.validate(ResourceScript); err != nil {
+		return nil, err
+	}
+	scripts, err := c.getScripts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []Script
+	for _, s := range scripts {
+		if opts.match(s.Name, s.Type, "") {
+			result = append(result, s)
 		}
 	}
+	return result, nil
 }
 
-func ScriptsInit() {
+// GetScript returns a script available in nexus
+func (c *Client) GetScript(ctx context.Context, name string) (Script, error) {
+	return c.getScript(ctx, name)
+}
+
+func (c *Client) ScriptsInit(ctx context.Context) error {
 	for _, s := range NexusScripts {
-		AddOrUpdateScript(s)
+		if err := c.AddOrUpdateScript(ctx, s); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func AddScript(name string) {
+func (c *Client) AddScript(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
-	url := fmt.Sprintf("%s/%s/%s", NexusURL, apiBase, scriptAPI)
-	if !scriptExists(name) {
-		payload, err := json.Marshal(Script{Name: name, Type: "groovy", Content: readFile(getScriptPath(name))})
-		logJsonMarshalError(err, getfuncName())
-		req := createBaseRequest("POST", url, RequestBody{Json: payload})
-		_, status := httpRequest(req)
-		if status == noContentStatus {
-			if Debug {
-				log.Printf(scriptAddedInfo, name)
-			}
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
-		}
-	} else {
-		log.Printf(scriptExistsInfo, name)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	}
+	exists, err := c.scriptExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrScriptExists, scriptExistsInfo, name)
+	}
+	content, err := c.getScriptContent(name)
+	if err != nil {
+		return err
+	}
+	return c.uploadScript(ctx, Script{Name: name, Type: "groovy", Content: content}, false)
 }
 
-func UpdateScript(name string) {
+func (c *Client) UpdateScript(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
-	url := fmt.Sprintf("%s/%s/%s/%s", NexusURL, apiBase, scriptAPI, name)
-	if scriptExists(name) {
-		payload, err := json.Marshal(Script{Name: name, Type: "groovy", Content: readFile(getScriptPath(name))})
-		logJsonMarshalError(err, getfuncName())
-		req := createBaseRequest("PUT", url, RequestBody{Json: payload})
-		_, status := httpRequest(req)
-		if status == noContentStatus {
-			if Debug {
-				log.Printf(scriptUpdatedInfo, name)
-			}
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
-		}
-	} else {
-		log.Printf(scriptNotfoundInfo, name)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	}
+	exists, err := c.scriptExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrScriptNotFound, scriptNotfoundInfo, name)
+	}
+	content, err := c.getScriptContent(name)
+	if err != nil {
+		return err
+	}
+	return c.uploadScript(ctx, Script{Name: name, Type: "groovy", Content: content}, true)
 }
 
-func AddOrUpdateScript(name string) {
-	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+// uploadScript adds a script to nexus or updates the script when update is set
+func (c *Client) uploadScript(ctx context.Context, script Script, update bool) error {
+	payload, err := json.Marshal(script)
+	if err != nil {
+		return fmt.Errorf("%s : %w", jsonMarshalError, err)
 	}
-	if !scriptExists(name) {
-		AddScript(name)
-	} else {
-		UpdateScript(name)
+	method, url, info := "POST", fmt.Sprintf("%s/%s/%s", c.baseURL, apiBase, scriptAPI), scriptAddedInfo
+	if update {
+		method, url, info = "PUT", fmt.Sprintf("%s/%s", url, script.Name), scriptUpdatedInfo
+	}
+	if c.plan != nil {
+		action := ActionCreate
+		if update {
+			action = ActionUpdate
+		}
+		c.plan.add(Change{Resource: ResourceScript, Name: script.Name, Action: action, After: script})
+		return nil
+	}
+	respBody, status, err := c.doRequest(ctx, method, url, RequestBody{Json: payload})
+	if err != nil {
+		return err
+	}
+	if status == goneStatus {
+		return newError(ErrNotSupported, scriptAPIDisabledInfo, script.Name)
+	} else if status != noContentStatus {
+		return newAPIError(method, url, status, respBody)
+	}
+	if c.debug {
+		c.logger.Printf(info, script.Name)
 	}
+	return nil
 }
 
-func DeleteScript(name string) {
+func (c *Client) AddOrUpdateScript(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
-	url := fmt.Sprintf("%s/%s/%s/%s", NexusURL, apiBase, scriptAPI, name)
-	if scriptExists(name) {
-		req := createBaseRequest("Delete", url, RequestBody{Json: nil})
-		_, status := httpRequest(req)
-		if status == noContentStatus {
-			if Debug {
-				log.Printf(scriptDeletedInfo, name)
-			}
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
-		}
-	} else {
-		log.Printf(scriptNotfoundInfo, name)
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	exists, err := c.scriptExists(ctx, name)
+	if err != nil {
+		return err
 	}
+	if !exists {
+		return c.AddScript(ctx, name)
+	}
+	return c.UpdateScript(ctx, name)
 }
 
-func RunScript(name, payload string) ScriptResult {
+func (c *Client) DeleteScript(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	exists, err := c.scriptExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return newError(ErrScriptNotFound, scriptNotfoundInfo, name)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceScript, Name: name, Action: ActionDelete})
+		return nil
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s", c.baseURL, apiBase, scriptAPI, name)
+	respBody, status, err := c.doRequest(ctx, "DELETE", url, RequestBody{Json: nil})
+	if err != nil {
+		return err
+	}
+	if status != noContentStatus {
+		return newAPIError("DELETE", url, status, respBody)
 	}
+	if c.debug {
+		c.logger.Printf(scriptDeletedInfo, name)
+	}
+	return nil
+}
+
+// RunScript executes the script in nexus with the payload as argument and returns the result of the script.
+// The status of the result is set by the script and is checked by the caller
+func (c *Client) RunScript(ctx context.Context, name, payload string) (ScriptResult, error) {
 	var (
 		output ScriptOutput
 		result ScriptResult
 	)
-	url := fmt.Sprintf("%s/%s/%s/%s/run", NexusURL, apiBase, scriptAPI, name)
-	req := createBaseRequest("POST", url, RequestBody{Text: payload})
-	respBody, status := httpRequest(req)
-	if status == successStatus {
-		if Debug {
-			log.Printf(scriptRunSuccessInfo, name)
-		}
-	} else if status == notFoundStatus {
-		log.Printf(scriptRunNotFoundInfo, name)
-		os.Exit(1)
-	} else {
-		log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-		os.Exit(1)
+	if name == "" {
+		return result, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s/run", c.baseURL, apiBase, scriptAPI, name)
+	if entryExists(readOnlyScripts, name) {
+		ctx = withRetrySafe(ctx)
+	}
+	respBody, status, err := c.doRequest(ctx, "POST", url, RequestBody{Text: payload})
+	if err != nil {
+		return result, err
 	}
-	err := json.Unmarshal(respBody, &output)
-	logJsonUnmarshalError(err, getfuncName())
-	err = json.Unmarshal([]byte(output.Result), &result)
-	logJsonUnmarshalError(err, getfuncName())
-	return result
+	if status == notFoundStatus {
+		return result, newError(ErrScriptNotFound, scriptRunNotFoundInfo, name)
+	} else if status != successStatus {
+		return result, newAPIError("POST", url, status, respBody)
+	}
+	if c.debug {
+		c.logger.Printf(scriptRunSuccessInfo, name)
+	}
+	if err := json.Unmarshal(respBody, &output); err != nil {
+		return result, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	if err := json.Unmarshal([]byte(output.Result), &result); err != nil {
+		return result, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	return result, nil
 }
 
-func getScripts() []string {
+// scriptResultError returns an APIError for an unexpected status returned by a script
+func (c *Client) scriptResultError(name string, result ScriptResult) error {
+	url := fmt.Sprintf("%s/%s/%s/%s/run", c.baseURL, apiBase, scriptAPI, name)
+	return newAPIError("POST", url, result.Status, []byte(result.Message))
+}
+
+func (c *Client) getScripts(ctx context.Context) ([]Script, error) {
 	var (
-		url         = fmt.Sprintf("%s/%s/%s", NexusURL, apiBase, scriptAPI)
-		scripts     []Script
-		scriptsList []string
+		url     = fmt.Sprintf("%s/%s/%s", c.baseURL, apiBase, scriptAPI)
+		scripts []Script
 	)
-	req := createBaseRequest("GET", url, RequestBody{})
-	respBody, status := httpRequest(req)
+	respBody, status, err := c.doRequest(ctx, "GET", url, RequestBody{})
+	if err != nil {
+		return nil, err
+	}
 	if status != successStatus {
-		log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-		os.Exit(1)
-	} else {
-		err := json.Unmarshal(respBody, &scripts)
-		logJsonUnmarshalError(err, getfuncName())
-		for _, s := range scripts {
-			scriptsList = append(scriptsList, s.Name)
-		}
+		return nil, newAPIError("GET", url, status, respBody)
 	}
-	return scriptsList
+	if err := json.Unmarshal(respBody, &scripts); err != nil {
+		return nil, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
+	}
+	return scripts, nil
 }
 
-func getScript(name string) Script {
-	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
+func (c *Client) getScript(ctx context.Context, name string) (Script, error) {
 	var (
-		url    = fmt.Sprintf("%s/%s/%s/%s", NexusURL, apiBase, scriptAPI, name)
+		url    = fmt.Sprintf("%s/%s/%s/%s", c.baseURL, apiBase, scriptAPI, name)
 		script Script
 	)
-	req := createBaseRequest("GET", url, RequestBody{})
-	respBody, status := httpRequest(req)
-	if status == successStatus {
-		err := json.Unmarshal(respBody, &script)
-		logJsonUnmarshalError(err, getfuncName())
-	} else if status == notFoundStatus {
-		log.Printf(scriptNotfoundInfo, name)
-		os.Exit(1)
-	} else {
-		log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-		os.Exit(1)
+	if name == "" {
+		return script, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	respBody, status, err := c.doRequest(ctx, "GET", url, RequestBody{})
+	if err != nil {
+		return script, err
+	}
+	if status == notFoundStatus {
+		return script, newError(ErrScriptNotFound, scriptNotfoundInfo, name)
+	} else if status != successStatus {
+		return script, newAPIError("GET", url, status, respBody)
+	}
+	if err := json.Unmarshal(respBody, &script); err != nil {
+		return script, fmt.Errorf("%s : %w", jsonUnmarshalError, err)
 	}
-	return script
+	return script, nil
 }
 
 func getScriptPath(name string) string {
-	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
 	return fmt.Sprintf("%s/%s.groovy", scriptBasePath, name)
 }
 
-func scriptExists(name string) bool {
+// getScriptContent returns the content of a script.
+// A script found in the scripts directory of the client overrides the script embedded in the library
+func (c *Client) getScriptContent(name string) (string, error) {
+	if c.scriptsDir != "" {
+		path := filepath.Join(c.scriptsDir, fmt.Sprintf("%s.groovy", name))
+		if fileExists(path) {
+			return readFile(path)
+		}
+	}
+	content, err := embeddedScripts.ReadFile(getScriptPath(name))
+	if err != nil {
+		return "", newError(ErrScriptNotFound, scriptNotAvailableInfo, name)
+	}
+	return string(content), nil
+}
+
+func (c *Client) scriptExists(ctx context.Context, name string) (bool, error) {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return false, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s", c.baseURL, apiBase, scriptAPI, name)
+	respBody, status, err := c.doRequest(ctx, "GET", url, RequestBody{})
+	if err != nil {
+		return false, err
 	}
-	url := fmt.Sprintf("%s/%s/%s/%s", NexusURL, apiBase, scriptAPI, name)
-	req := createBaseRequest("GET", url, RequestBody{})
-	_, status := httpRequest(req)
 	if status == successStatus {
-		return true
+		return true, nil
+	} else if status == notFoundStatus {
+		return false, nil
 	}
-	return false
+	return false, newAPIError("GET", url, status, respBody)
 }
@@ -0,0 +1,162 @@
+package nxrm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// scriptBackend manages the resources using the groovy scripts executed through the script API
+type scriptBackend struct {
+	c *Client
+}
+
+func (b *scriptBackend) name() Backend {
+	return BackendScript
+}
+
+// run marshals the payload, runs the script and checks the status returned by the script.
+// notFound and found are returned when the script returns the corresponding status and are optional
+func (b *scriptBackend) run(ctx context.Context, script string, payload interface{}, notFound, found error) (ScriptResult, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ScriptResult{}, fmt.Errorf("%s : %w", jsonMarshalError, err)
+	}
+	result, err := b.c.RunScript(ctx, script, string(data))
+	if err != nil {
+		return result, err
+	}
+	switch {
+	case result.Status == successStatus:
+		return result, nil
+	case result.Status == notFoundStatus && notFound != nil:
+		return result, notFound
+	case result.Status == foundStatus && found != nil:
+		return result, found
+	}
+	return result, b.c.scriptResultError(script, result)
+}
+
+func (b *scriptBackend) getRepository(ctx context.Context, name string) (Repository, error) {
+	notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, name)
+	result, err := b.run(ctx, getRepoScript, Repository{Name: name}, notFound, nil)
+	if err != nil {
+		return Repository{}, err
+	}
+	return Repository{Name: result.Name, URL: result.URL, Type: result.Type, Format: result.Format, Recipe: result.Recipe, Online: result.Online, Attributes: result.Attributes}, nil
+}
+
+func (b *scriptBackend) createRepository(ctx context.Context, repository Repository) error {
+	var script string
+	switch {
+	case strings.HasSuffix(repository.Recipe, "-hosted"):
+		script = createHostedRepoScript
+	case strings.HasSuffix(repository.Recipe, "-proxy"):
+		script = createProxyRepoScript
+	case strings.HasSuffix(repository.Recipe, "-group"):
+		script = createGroupRepoScript
+	default:
+		return newError(ErrInvalidInput, recipeNotValidInfo, repository.Recipe)
+	}
+	found := newError(ErrRepositoryExists, repoExistsInfo, repository.Name)
+	_, err := b.run(ctx, script, repository, nil, found)
+	return err
+}
+
+func (b *scriptBackend) updateGroupMembers(ctx context.Context, repository Repository) error {
+	notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, repository.Name)
+	_, err := b.run(ctx, updateGroupMembersScript, repository, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) updateRepository(ctx context.Context, repository Repository) error {
+	notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, repository.Name)
+	_, err := b.run(ctx, updateRepoScript, repository, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) deleteRepository(ctx context.Context, name string) error {
+	notFound := newError(ErrRepositoryNotFound, repositoryNotFoundInfo, name)
+	_, err := b.run(ctx, deleteRepoScript, Repository{Name: name}, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) getSelectors(ctx context.Context) ([]ContentSelector, error) {
+	result, err := b.run(ctx, getSelectorsScript, ContentSelector{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.ContentSelectors, nil
+}
+
+func (b *scriptBackend) createSelector(ctx context.Context, selector ContentSelector) error {
+	found := newError(ErrSelectorExists, selectorAlreadyExistsInfo, selector.Name)
+	_, err := b.run(ctx, createSelectorScript, selector, nil, found)
+	return err
+}
+
+func (b *scriptBackend) updateSelector(ctx context.Context, selector ContentSelector) error {
+	notFound := newError(ErrSelectorNotFound, selectorNotFoundInfo, selector.Name)
+	_, err := b.run(ctx, updateSelectorScript, selector, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) deleteSelector(ctx context.Context, selector ContentSelector) error {
+	notFound := newError(ErrSelectorNotFound, selectorNotFoundInfo, selector.Name)
+	_, err := b.run(ctx, deleteSelectorScript, selector, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) getPrivileges(ctx context.Context) ([]Privilege, error) {
+	result, err := b.run(ctx, getPrivilegesScript, Privilege{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.Privileges, nil
+}
+
+func (b *scriptBackend) createPrivilege(ctx context.Context, privilege Privilege) error {
+	found := newError(ErrPrivilegeExists, privilegeExistsInfo, privilege.Name)
+	_, err := b.run(ctx, createPrivilegeScript, privilege, nil, found)
+	return err
+}
+
+func (b *scriptBackend) updatePrivilege(ctx context.Context, privilege Privilege) error {
+	notFound := newError(ErrPrivilegeNotFound, privilegeNotFoundInfo, privilege.Name)
+	_, err := b.run(ctx, updatePrivilegeScript, privilege, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) deletePrivilege(ctx context.Context, privilege Privilege) error {
+	notFound := newError(ErrPrivilegeNotFound, privilegeNotFoundInfo, privilege.Name)
+	_, err := b.run(ctx, deletePrivilegeScript, Privilege{ID: privilege.ID}, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) getRoles(ctx context.Context) ([]Role, error) {
+	result, err := b.run(ctx, getRoleScript, Role{}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result.Roles, nil
+}
+
+func (b *scriptBackend) createRole(ctx context.Context, role Role) error {
+	found := newError(ErrRoleExists, roleExistsInfo, role.RoleID)
+	_, err := b.run(ctx, createRoleScript, role, nil, found)
+	return err
+}
+
+// updateRole replaces the role as there is no script to update a role
+func (b *scriptBackend) updateRole(ctx context.Context, role Role) error {
+	notFound := newError(ErrRoleNotFound, roleNotFoundInfo, role.RoleID)
+	_, err := b.run(ctx, updateRoleScript, role, notFound, nil)
+	return err
+}
+
+func (b *scriptBackend) deleteRole(ctx context.Context, id string) error {
+	notFound := newError(ErrRoleNotFound, roleNotFoundInfo, id)
+	_, err := b.run(ctx, deleteRoleScript, Role{RoleID: id}, notFound, nil)
+	return err
+}
@@ -0,0 +1,21 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.selector.SelectorConfiguration
+import org.sonatype.nexus.selector.SelectorManager
+
+def params = new JsonSlurper().parseText(args)
+def selectorManager = container.lookup(SelectorManager.class.name)
+if (selectorManager.browse().find { it.name == params.name } != null) {
+    return JsonOutput.toJson([status: "302 Found", message: "Content selector ${params.name} already exists".toString()])
+}
+
+def attributes = [expression: params.attributes.expression]
+def selector
+if (selectorManager.respondsTo("newSelectorConfiguration")) {
+    selector = selectorManager.newSelectorConfiguration(params.name, params.type, params.description, attributes)
+} else {
+    selector = new SelectorConfiguration(name: params.name, type: params.type, description: params.description, attributes: attributes)
+}
+selectorManager.create(selector)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
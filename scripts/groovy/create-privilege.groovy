@@ -0,0 +1,21 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.security.privilege.Privilege
+
+def params = new JsonSlurper().parseText(args)
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+if (authorizationManager.listPrivileges().find { it.id == params.id } != null) {
+    return JsonOutput.toJson([status: "302 Found", message: "Privilege ${params.id} already exists".toString()])
+}
+
+def privilege = new Privilege(
+        id: params.id,
+        name: params.name,
+        description: params.description,
+        type: params.type,
+        properties: params.properties.findAll { k, v -> v != null && v != "" },
+        readOnly: false
+)
+authorizationManager.addPrivilege(privilege)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
@@ -0,0 +1,48 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.repository.routing.RoutingRuleStore
+import org.sonatype.nexus.repository.config.Configuration
+
+// clean removes the attributes which are not set so that nexus applies its defaults
+def clean
+clean = { Map attributes ->
+    attributes.collectEntries { k, v ->
+        [k, v instanceof Map ? clean(v) : v]
+    }.findAll { k, v ->
+        !(v == null || v == "" || v == 0 || (v instanceof Map && v.isEmpty()) || (v instanceof Collection && v.isEmpty()))
+    }
+}
+
+def params = new JsonSlurper().parseText(args)
+def repositoryManager = repository.repositoryManager
+if (repositoryManager.get(params.name) != null) {
+    return JsonOutput.toJson([status: "302 Found", name: params.name, message: "Repository ${params.name} already exists".toString()])
+}
+
+def attributes = clean(params.attributes)
+// nexus 3.19 and later store the cleanup policies as a set
+if (attributes.cleanup?.policyName) {
+    attributes.cleanup.policyName = attributes.cleanup.policyName as Set
+}
+if (attributes.httpclient?.authentication?.username) {
+    attributes.httpclient.authentication.type = attributes.httpclient.authentication.type ?: "username"
+}
+
+// the routing rule is referenced by name in the library and by id in nexus
+if (attributes.routingRule) {
+    def ruleName = attributes.remove("routingRule")
+    def rule = container.lookup(RoutingRuleStore.class.name).getByName(ruleName)
+    if (rule == null) {
+        return JsonOutput.toJson([status: "400 Bad Request", message: "Routing rule ${ruleName} was not found".toString()])
+    }
+    attributes.routingRules = [routingRuleId: rule.id().value]
+}
+
+Configuration conf = repositoryManager.newConfiguration()
+conf.repositoryName = params.name
+conf.recipeName = params.recipe
+conf.online = params.online != false
+conf.attributes = attributes
+repositoryManager.create(conf)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
@@ -0,0 +1,22 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.security.role.Role
+
+def params = new JsonSlurper().parseText(args)
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+if (authorizationManager.listRoles().find { it.roleId == params.roleId } != null) {
+    return JsonOutput.toJson([status: "302 Found", message: "Role ${params.roleId} already exists".toString()])
+}
+
+def role = new Role(
+        roleId: params.roleId,
+        name: params.name,
+        description: params.description,
+        source: params.source,
+        readOnly: false,
+        roles: (params.roles ?: []) as Set,
+        privileges: (params.privileges ?: []) as Set
+)
+authorizationManager.addRole(role)
+
+return JsonOutput.toJson([status: "200 OK", roleId: params.roleId])
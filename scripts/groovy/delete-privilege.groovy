@@ -0,0 +1,12 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+
+def params = new JsonSlurper().parseText(args)
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+if (authorizationManager.listPrivileges().find { it.id == params.id } == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Privilege ${params.id} was not found".toString()])
+}
+
+authorizationManager.deletePrivilege(params.id)
+
+return JsonOutput.toJson([status: "200 OK", id: params.id])
@@ -0,0 +1,12 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+
+def params = new JsonSlurper().parseText(args)
+def repositoryManager = repository.repositoryManager
+if (repositoryManager.get(params.name) == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Repository ${params.name} was not found".toString()])
+}
+
+repositoryManager.delete(params.name)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
@@ -0,0 +1,12 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+
+def params = new JsonSlurper().parseText(args)
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+if (authorizationManager.listRoles().find { it.roleId == params.roleId } == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Role ${params.roleId} was not found".toString()])
+}
+
+authorizationManager.deleteRole(params.roleId)
+
+return JsonOutput.toJson([status: "200 OK", roleId: params.roleId])
@@ -0,0 +1,9 @@
+import groovy.json.JsonOutput
+import org.sonatype.nexus.selector.SelectorManager
+
+def selectorManager = container.lookup(SelectorManager.class.name)
+def selectors = selectorManager.browse().collect { s ->
+    [name: s.name, type: s.type, description: s.description, attributes: s.attributes]
+}
+
+return JsonOutput.toJson([status: "200 OK", contentSelectors: selectors])
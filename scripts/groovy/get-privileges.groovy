@@ -0,0 +1,8 @@
+import groovy.json.JsonOutput
+
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+def privileges = authorizationManager.listPrivileges().collect { p ->
+    [id: p.id, name: p.name, description: p.description, type: p.type, properties: p.properties, readOnly: p.readOnly]
+}
+
+return JsonOutput.toJson([status: "200 OK", privileges: privileges])
@@ -0,0 +1,27 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.repository.routing.RoutingRuleStore
+
+def params = new JsonSlurper().parseText(args)
+def repo = repository.repositoryManager.get(params.name)
+if (repo == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Repository ${params.name} was not found".toString()])
+}
+
+def conf = repo.configuration
+def attributes = conf.attributes.collectEntries { k, v -> [k, v] }
+// the routing rule is referenced by id in nexus and by name in the library
+def routingRuleId = attributes.remove("routingRules")?.routingRuleId
+if (routingRuleId) {
+    attributes.routingRule = container.lookup(RoutingRuleStore.class.name).getById(routingRuleId.toString())?.name()
+}
+return JsonOutput.toJson([
+        status    : "200 OK",
+        name      : repo.name,
+        url       : repo.url,
+        type      : repo.type.value,
+        format    : repo.format.value,
+        recipe    : conf.recipeName,
+        online    : conf.online,
+        attributes: attributes
+])
@@ -0,0 +1,8 @@
+import groovy.json.JsonOutput
+
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+def roles = authorizationManager.listRoles().collect { r ->
+    [roleId: r.roleId, name: r.name, description: r.description, source: r.source, roles: r.roles, privileges: r.privileges, readOnly: r.readOnly]
+}
+
+return JsonOutput.toJson([status: "200 OK", roles: roles])
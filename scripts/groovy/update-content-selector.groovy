@@ -0,0 +1,16 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.selector.SelectorManager
+
+def params = new JsonSlurper().parseText(args)
+def selectorManager = container.lookup(SelectorManager.class.name)
+def selector = selectorManager.browse().find { it.name == params.name }
+if (selector == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Content selector ${params.name} was not found".toString()])
+}
+
+selector.description = params.description
+selector.attributes = [expression: params.attributes.expression]
+selectorManager.update(selector)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
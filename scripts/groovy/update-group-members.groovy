@@ -0,0 +1,15 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+
+def params = new JsonSlurper().parseText(args)
+def repositoryManager = repository.repositoryManager
+def repo = repositoryManager.get(params.name)
+if (repo == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Repository ${params.name} was not found".toString()])
+}
+
+def conf = repo.configuration.copy()
+conf.attributes("group").set("memberNames", params.attributes.group.memberNames)
+repositoryManager.update(conf)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
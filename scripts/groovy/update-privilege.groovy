@@ -0,0 +1,15 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+
+def params = new JsonSlurper().parseText(args)
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+def privilege = authorizationManager.listPrivileges().find { it.id == params.id }
+if (privilege == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Privilege ${params.id} was not found".toString()])
+}
+
+privilege.description = params.description
+privilege.properties = params.properties.findAll { k, v -> v != null && v != "" }
+authorizationManager.updatePrivilege(privilege)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
@@ -0,0 +1,80 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+import org.sonatype.nexus.repository.routing.RoutingRuleStore
+
+// isSet reports whether an attribute or one of the attributes of a section is set
+def isSet
+isSet = { v -> v instanceof Map ? v.values().any { isSet(it) } : v as boolean }
+
+// merge sets the attributes of the library in the stored attributes, so that the attributes which are not modeled by
+// the library keep their value. The attributes which are not set are removed, except the password which is not
+// returned to the library and the docker ports set to 0 which disable the connector. The sections which are not
+// stored are only added when one of their attributes is set
+def merge
+merge = { Map stored, Map attributes ->
+    attributes.each { k, v ->
+        if (v instanceof Map) {
+            if (stored[k] instanceof Map || isSet(v)) {
+                def section = stored[k] instanceof Map ? new LinkedHashMap(stored[k]) : [:]
+                merge(section, v)
+                if (section.isEmpty()) {
+                    stored.remove(k)
+                } else {
+                    stored[k] = section
+                }
+            }
+        } else if (v == null || v == "" || (v == 0 && k in ["httpPort", "httpsPort"]) || (v instanceof Collection && v.isEmpty())) {
+            if (k != "password") {
+                stored.remove(k)
+            }
+        } else {
+            stored[k] = v
+        }
+    }
+    return stored
+}
+
+def params = new JsonSlurper().parseText(args)
+def repositoryManager = repository.repositoryManager
+def repo = repositoryManager.get(params.name)
+if (repo == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Repository ${params.name} was not found".toString()])
+}
+
+def attributes = params.attributes ?: [:]
+// nexus 3.19 and later store the cleanup policies as a set
+if (attributes.cleanup?.policyName) {
+    attributes.cleanup.policyName = attributes.cleanup.policyName as Set
+}
+def authentication = attributes.httpclient?.authentication
+if (authentication != null) {
+    if (authentication.username) {
+        authentication.type = authentication.type ?: "username"
+    } else {
+        // the credentials are removed when the username is not set
+        attributes.httpclient.authentication = null
+    }
+}
+
+// the routing rule is referenced by name in the library and by id in nexus
+if (attributes.containsKey("routingRule")) {
+    def ruleName = attributes.remove("routingRule")
+    attributes.routingRules = null
+    if (ruleName) {
+        def rule = container.lookup(RoutingRuleStore.class.name).getByName(ruleName)
+        if (rule == null) {
+            return JsonOutput.toJson([status: "400 Bad Request", message: "Routing rule ${ruleName} was not found".toString()])
+        }
+        attributes.routingRules = [routingRuleId: rule.id().value]
+    }
+}
+
+def conf = repo.configuration.copy()
+def stored = conf.attributes.collectEntries { k, v -> [k, v instanceof Map ? new LinkedHashMap(v) : v] }
+conf.attributes = merge(stored, attributes)
+if (params.online != null) {
+    conf.online = params.online
+}
+repositoryManager.update(conf)
+
+return JsonOutput.toJson([status: "200 OK", name: params.name])
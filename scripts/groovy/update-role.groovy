@@ -0,0 +1,18 @@
+import groovy.json.JsonOutput
+import groovy.json.JsonSlurper
+
+def params = new JsonSlurper().parseText(args)
+def authorizationManager = security.securitySystem.getAuthorizationManager("default")
+def role = authorizationManager.listRoles().find { it.roleId == params.roleId }
+if (role == null) {
+    return JsonOutput.toJson([status: "404 Not Found", message: "Role ${params.roleId} was not found".toString()])
+}
+
+// the role is updated in place so that the users keep it
+role.name = params.name
+role.description = params.description
+role.roles = (params.roles ?: []) as Set
+role.privileges = (params.privileges ?: []) as Set
+authorizationManager.updateRole(role)
+
+return JsonOutput.toJson([status: "200 OK", roleId: params.roleId])
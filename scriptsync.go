@@ -0,0 +1,93 @@
+package nxrm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+)
+
+// SyncScriptsOptions configures SyncScripts
+type SyncScriptsOptions struct {
+	// DryRun only reports the drift without changing the scripts in nexus
+	DryRun bool
+	// Prune deletes the scripts available in nexus which are not listed in NexusScripts
+	Prune bool
+}
+
+// ScriptSyncReport lists the scripts per sync outcome
+type ScriptSyncReport struct {
+	// Added are the managed scripts which were missing in nexus
+	Added []string `json:"added"`
+	// Changed are the managed scripts whose content in nexus differs from the local version
+	Changed []string `json:"changed"`
+	// Unchanged are the managed scripts which are up to date in nexus
+	Unchanged []string `json:"unchanged"`
+	// Extra are the scripts available in nexus which are not managed by the library
+	Extra []string `json:"extra"`
+	// Pruned are the extra scripts which were deleted from nexus
+	Pruned []string `json:"pruned"`
+}
+
+// InSync returns true when no managed script is missing or changed
+func (r ScriptSyncReport) InSync() bool {
+	return len(r.Added) == 0 && len(r.Changed) == 0
+}
+
+// SyncScripts compares the checksums of the scripts installed in nexus with the embedded or overridden
+// scripts listed in NexusScripts and only uploads the scripts which are missing or changed
+func (c *Client) SyncScripts(ctx context.Context, opts SyncScriptsOptions) (ScriptSyncReport, error) {
+	var report ScriptSyncReport
+	installed, err := c.getScripts(ctx)
+	if err != nil {
+		return report, err
+	}
+	installedChecksums := make(map[string]string)
+	for _, s := range installed {
+		installedChecksums[s.Name] = scriptChecksum(s.Content)
+	}
+
+	for _, name := range NexusScripts {
+		content, err := c.getScriptContent(name)
+		if err != nil {
+			return report, err
+		}
+		checksum, exists := installedChecksums[name]
+		if exists && checksum == scriptChecksum(content) {
+			report.Unchanged = append(report.Unchanged, name)
+			continue
+		}
+		if !opts.DryRun {
+			if err := c.uploadScript(ctx, Script{Name: name, Type: "groovy", Content: content}, exists); err != nil {
+				return report, err
+			}
+		}
+		if exists {
+			report.Changed = append(report.Changed, name)
+		} else {
+			report.Added = append(report.Added, name)
+		}
+	}
+
+	for _, s := range installed {
+		if entryExists(NexusScripts, s.Name) {
+			continue
+		}
+		report.Extra = append(report.Extra, s.Name)
+		if opts.Prune && !opts.DryRun {
+			if err := c.DeleteScript(ctx, s.Name); err != nil {
+				return report, err
+			}
+			report.Pruned = append(report.Pruned, s.Name)
+		}
+	}
+	sort.Strings(report.Extra)
+	sort.Strings(report.Pruned)
+	return report, nil
+}
+
+// scriptChecksum returns the sha256 checksum of the content of a script
+func scriptChecksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,10 +1,8 @@
 package nxrm
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"log"
-	"os"
 )
 
 type ContentSelector struct {
@@ -18,128 +16,162 @@ type ContentSelectorAttributes struct {
 	Expression string `json:"expression"`
 }
 
-func ListSelectors(name string) {
+// ListSelectors prints the details of a content selector when a name is provided,
+// otherwise prints the names of all the content selectors
+func (c *Client) ListSelectors(ctx context.Context, name string) error {
 	if name != "" {
-		cs := getSelector(name)
+		cs, err := c.GetSelector(ctx, name)
+		if err != nil {
+			return err
+		}
 		fmt.Printf("Name: %s\nDescription: %s\nExpression: %s\n",
 			cs.Name, cs.Description, cs.Attributes.Expression)
-	} else {
-		csNames := getSelectorNames()
-		printStringSlice(csNames)
-		fmt.Printf("Total Number of content selectors : %d\n", len(csNames))
+		return nil
+	}
+	selectors, err := c.GetSelectors(ctx, ListOptions{})
+	if err != nil {
+		return err
 	}
+	for _, cs := range selectors {
+		fmt.Println(cs.Name)
+	}
+	fmt.Printf("Total Number of content selectors : %d\n", len(selectors))
+	return nil
 }
 
-func CreateSelector(name, description, expression string) {
-	if name == "" || expression == "" {
-		log.Printf("%s : %s", getfuncName(), createSelectorRequiredInfo)
-		os.Exit(1)
-	}
-	if !selectorExists(name) {
-		attributes := ContentSelectorAttributes{Expression: expression}
-		payload, err := json.Marshal(ContentSelector{Name: name, Type: contentSelectorType, Description: getSelectorDescription(description), Attributes: attributes})
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript("create-content-selector", string(payload))
-		if result.Status == successStatus {
-			log.Printf(createSelectorSuccessInfo, name)
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
+// GetSelectors returns the content selectors matching the options
+func (c *Client) GetSelectors(ctx context.Context, opts ListOptions) ([]ContentSelector, error) {
+	if err := opts.validate(ResourceSelector); err != nil {
+		return nil, err
+	}
+	selectors, err := c.getSelectors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []ContentSelector
+	for _, cs := range selectors {
+		if opts.match(cs.Name, cs.Type, "") {
+			result = append(result, cs)
 		}
-	} else {
-		log.Printf(selectorAlreadyExistsInfo, name)
 	}
+	return result, nil
+}
+
+// GetSelector returns a content selector
+func (c *Client) GetSelector(ctx context.Context, name string) (ContentSelector, error) {
+	return c.getSelector(ctx, name)
+}
+
+func (c *Client) CreateSelector(ctx context.Context, name, description, expression string) error {
+	if name == "" || expression == "" {
+		return newError(ErrInvalidInput, createSelectorRequiredInfo)
+	}
+	exists, err := c.selectorExists(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrSelectorExists, selectorAlreadyExistsInfo, name)
+	}
+	attributes := ContentSelectorAttributes{Expression: expression}
+	selector := ContentSelector{Name: name, Type: contentSelectorType, Description: getSelectorDescription(description), Attributes: attributes}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.createSelector(ctx, selector); err != nil {
+		return err
+	}
+	c.logger.Printf(createSelectorSuccessInfo, name)
+	return nil
 }
 
-func UpdateSelector(name, description, expression string) {
+func (c *Client) UpdateSelector(ctx context.Context, name, description, expression string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	}
-	if selectorExists(name) {
-		selector := getSelector(name)
-		if description != "" {
-			selector.Description = description
-		}
-		if expression != "" {
-			selector.Attributes = ContentSelectorAttributes{Expression: expression}
-		}
-		payload, err := json.Marshal(selector)
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript("update-content-selector", string(payload))
-		if result.Status == successStatus {
-			log.Printf(updateSelectorSuccessInfo, name)
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
-		}
-	} else {
-		log.Printf(selectorNotFoundInfo, name)
+	selector, err := c.getSelector(ctx, name)
+	if err != nil {
+		return err
+	}
+	if description != "" {
+		selector.Description = description
 	}
+	if expression != "" {
+		selector.Attributes = ContentSelectorAttributes{Expression: expression}
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.updateSelector(ctx, selector); err != nil {
+		return err
+	}
+	c.logger.Printf(updateSelectorSuccessInfo, name)
+	return nil
 }
 
-func DeleteSelector(name string) {
+func (c *Client) DeleteSelector(ctx context.Context, name string) error {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
-	}
-	if selectorExists(name) {
-		selector := getSelector(name)
-		payload, err := json.Marshal(selector)
-		logJsonMarshalError(err, jsonMarshalError)
-		result := RunScript("delete-content-selector", string(payload))
-		if result.Status == successStatus {
-			log.Printf(deleteSelectorSuccessInfo, name)
-		} else {
-			log.Printf("%s : %s", getfuncName(), setVerboseInfo)
-			os.Exit(1)
-		}
-	} else {
-		log.Printf(selectorNotFoundInfo, name)
+		return newError(ErrInvalidInput, nameRequiredInfo)
 	}
+	selector, err := c.getSelector(ctx, name)
+	if err != nil {
+		return err
+	}
+	b, err := c.backend(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.deleteSelector(ctx, selector); err != nil {
+		return err
+	}
+	c.logger.Printf(deleteSelectorSuccessInfo, name)
+	return nil
 }
 
-func getSelectors() []ContentSelector {
-	payload, err := json.Marshal(Repository{})
-	logJsonMarshalError(err, getfuncName())
-	result := RunScript("get-content-selectors", string(payload))
-	return result.ContentSelectors
+func (c *Client) getSelectors(ctx context.Context) ([]ContentSelector, error) {
+	b, err := c.backend(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return b.getSelectors(ctx)
 }
 
-func getSelector(name string) ContentSelector {
+func (c *Client) getSelector(ctx context.Context, name string) (ContentSelector, error) {
 	if name == "" {
-		log.Printf("%s : %s", getfuncName(), nameRequiredInfo)
-		os.Exit(1)
+		return ContentSelector{}, newError(ErrInvalidInput, nameRequiredInfo)
+	}
+	contentSelectors, err := c.getSelectors(ctx)
+	if err != nil {
+		return ContentSelector{}, err
 	}
-	var contentSelector ContentSelector
-	contentSelectors := getSelectors()
 	for _, cs := range contentSelectors {
 		if cs.Name == name {
-			contentSelector = cs
+			return cs, nil
 		}
 	}
-	if contentSelector.Name == "" {
-		log.Printf(selectorNotFoundInfo, name)
-		os.Exit(1)
-	}
-	return contentSelector
+	return ContentSelector{}, newError(ErrSelectorNotFound, selectorNotFoundInfo, name)
 }
 
-func getSelectorNames() []string {
+func (c *Client) getSelectorNames(ctx context.Context) ([]string, error) {
 	var csNames []string
-	contentSelectors := getSelectors()
+	contentSelectors, err := c.getSelectors(ctx)
+	if err != nil {
+		return nil, err
+	}
 	for _, cs := range contentSelectors {
 		csNames = append(csNames, cs.Name)
 	}
-	return csNames
+	return csNames, nil
 }
 
-func selectorExists(name string) bool {
-	csNames := getSelectorNames()
-	if entryExists(csNames, name) {
-		return true
+func (c *Client) selectorExists(ctx context.Context, name string) (bool, error) {
+	csNames, err := c.getSelectorNames(ctx)
+	if err != nil {
+		return false, err
 	}
-	return false
+	return entryExists(csNames, name), nil
 }
 
 func getSelectorDescription(description string) string {
@@ -0,0 +1,390 @@
+package nxrm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+const (
+	UserActive   = "active"
+	UserDisabled = "disabled"
+
+	// UserSourceDefault is the source of the users stored in nexus
+	UserSourceDefault = "default"
+
+	ResourceUser = "user"
+)
+
+type User struct {
+	UserID        string   `json:"userId"`
+	FirstName     string   `json:"firstName"`
+	LastName      string   `json:"lastName"`
+	EmailAddress  string   `json:"emailAddress"`
+	Source        string   `json:"source"`
+	Status        string   `json:"status"`
+	ReadOnly      bool     `json:"readOnly"`
+	Roles         []string `json:"roles"`
+	ExternalRoles []string `json:"externalRoles"`
+}
+
+// restNewUser is the request body to create a user
+type restNewUser struct {
+	UserID       string   `json:"userId"`
+	FirstName    string   `json:"firstName"`
+	LastName     string   `json:"lastName"`
+	EmailAddress string   `json:"emailAddress"`
+	Password     string   `json:"password"`
+	Status       string   `json:"status"`
+	Roles        []string `json:"roles"`
+}
+
+// ListUsers prints the details of a user when an id is provided,
+// otherwise prints the ids of all the users
+func (c *Client) ListUsers(ctx context.Context, id string) error {
+	if id != "" {
+		user, err := c.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ID: %s\nName: %s %s\nEmail: %s\nSource: %s\nStatus: %s\nRoles: %s\n",
+			user.UserID, user.FirstName, user.LastName, user.EmailAddress, user.Source, user.Status, user.Roles)
+		return nil
+	}
+	users, err := c.GetUsers(ctx, ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, u := range users {
+		fmt.Println(u.UserID)
+	}
+	fmt.Printf("Number of users : %d\n", len(users))
+	return nil
+}
+
+// GetUsers returns the local users matching the options, the type is matched against the source
+func (c *Client) GetUsers(ctx context.Context, opts ListOptions) ([]User, error) {
+	if err := opts.validate(ResourceUser); err != nil {
+		return nil, err
+	}
+	users, err := c.getUsers(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	var result []User
+	for _, u := range users {
+		if opts.match(u.UserID, u.Source, "") {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+// GetUser returns a user
+func (c *Client) GetUser(ctx context.Context, id string) (User, error) {
+	return c.getUser(ctx, id)
+}
+
+// CreateUser creates a local user with a password. The status defaults to UserActive and the roles must exist
+func (c *Client) CreateUser(ctx context.Context, user User, password string) error {
+	if user.Status == "" {
+		user.Status = UserActive
+	}
+	if err := validateUser(user); err != nil {
+		return err
+	}
+	if password == "" {
+		return newError(ErrInvalidInput, passwordRequiredInfo)
+	}
+	exists, err := c.userExists(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return newError(ErrUserExists, userExistsInfo, user.UserID)
+	}
+	if err := c.validateUserRoles(ctx, user.Roles); err != nil {
+		return err
+	}
+	user.Source = UserSourceDefault
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceUser, Name: user.UserID, Action: ActionCreate, After: user})
+	} else {
+		body := restNewUser{
+			UserID:       user.UserID,
+			FirstName:    user.FirstName,
+			LastName:     user.LastName,
+			EmailAddress: user.EmailAddress,
+			Password:     password,
+			Status:       user.Status,
+			Roles:        append([]string{}, user.Roles...),
+		}
+		if err := c.doSecurity(ctx, "POST", usersPath, body, nil, nil); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(userCreatedInfo, user.UserID)
+	return nil
+}
+
+// UpdateUser replaces the names, the email address, the status and the roles of a user.
+// Only the roles of the users of an external source eg: LDAP are updated
+func (c *Client) UpdateUser(ctx context.Context, user User) error {
+	before, err := c.getUser(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+	after := before
+	after.Roles = user.Roles
+	if before.Source == UserSourceDefault {
+		after.FirstName, after.LastName, after.EmailAddress, after.Status = user.FirstName, user.LastName, user.EmailAddress, user.Status
+	}
+	if err := validateUserUpdate(before, after); err != nil {
+		return err
+	}
+	if err := c.validateUserRoles(ctx, after.Roles); err != nil {
+		return err
+	}
+	return c.updateUser(ctx, before, after)
+}
+
+// ChangePassword changes the password of a local user
+func (c *Client) ChangePassword(ctx context.Context, id, password string) error {
+	if password == "" {
+		return newError(ErrInvalidInput, passwordRequiredInfo)
+	}
+	user, err := c.getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user.Source != UserSourceDefault {
+		return newError(ErrInvalidInput, userNotLocalInfo, id, user.Source)
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceUser, Name: id, Action: ActionUpdate})
+	} else {
+		url := fmt.Sprintf("%s/%s/%s/%s/change-password", c.baseURL, apiBase, usersPath, url.PathEscape(id))
+		respBody, status, err := c.doRequest(ctx, "PUT", url, RequestBody{Text: password})
+		if err != nil {
+			return err
+		}
+		if !isSuccessStatus(status) {
+			return newAPIError("PUT", url, status, respBody)
+		}
+	}
+	c.logger.Printf(passwordChangedInfo, id)
+	return nil
+}
+
+// DeleteUser deletes a user
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	before, err := c.getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceUser, Name: id, Action: ActionDelete, Before: before})
+	} else {
+		notFound := newError(ErrUserNotFound, userNotFoundInfo, id)
+		if err := c.doSecurity(ctx, "DELETE", fmt.Sprintf("%s/%s", usersPath, url.PathEscape(id)), nil, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(userDeletedInfo, id)
+	return nil
+}
+
+// AddRolesToUser grants roles to a user. The roles which the user already has are skipped
+func (c *Client) AddRolesToUser(ctx context.Context, id string, roles ...string) error {
+	if len(roles) < 1 {
+		return newError(ErrInvalidInput, userRolesRequiredInfo)
+	}
+	before, err := c.getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := c.validateUserRoles(ctx, roles); err != nil {
+		return err
+	}
+	after := before
+	after.Roles = append([]string(nil), before.Roles...)
+	var added []string
+	for _, role := range roles {
+		if entryExists(after.Roles, role) {
+			c.logger.Printf(userRoleAlreadyExistsInfo, role, id)
+			continue
+		}
+		after.Roles = append(after.Roles, role)
+		added = append(added, role)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	if err := c.updateUser(ctx, before, after); err != nil {
+		return err
+	}
+	for _, role := range added {
+		c.logger.Printf(userRoleAddedInfo, role, id)
+	}
+	return nil
+}
+
+// RemoveRolesFromUser revokes roles from a user. The roles which the user does not have are skipped
+func (c *Client) RemoveRolesFromUser(ctx context.Context, id string, roles ...string) error {
+	if len(roles) < 1 {
+		return newError(ErrInvalidInput, userRolesRequiredInfo)
+	}
+	before, err := c.getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	after := before
+	after.Roles = append([]string(nil), before.Roles...)
+	var removed []string
+	for _, role := range roles {
+		if !entryExists(after.Roles, role) {
+			c.logger.Printf(userRoleNotFoundInfo, role, id)
+			continue
+		}
+		after.Roles = removeEntryFromSlice(after.Roles, role)
+		removed = append(removed, role)
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	if err := c.updateUser(ctx, before, after); err != nil {
+		return err
+	}
+	for _, role := range removed {
+		c.logger.Printf(userRoleRemovedInfo, role, id)
+	}
+	return nil
+}
+
+func (c *Client) updateUser(ctx context.Context, before, after User) error {
+	if c.plan != nil {
+		c.plan.add(Change{Resource: ResourceUser, Name: after.UserID, Action: ActionUpdate, Before: before, After: after})
+	} else {
+		body := after
+		body.Roles = append([]string{}, after.Roles...)
+		notFound := newError(ErrUserNotFound, userNotFoundInfo, after.UserID)
+		if err := c.doSecurity(ctx, "PUT", fmt.Sprintf("%s/%s", usersPath, url.PathEscape(after.UserID)), body, nil, notFound); err != nil {
+			return err
+		}
+	}
+	c.logger.Printf(userUpdatedInfo, after.UserID)
+	return nil
+}
+
+// getUsers returns the users whose id starts with prefix
+func (c *Client) getUsers(ctx context.Context, prefix string) ([]User, error) {
+	path := usersPath
+	if prefix != "" {
+		path = fmt.Sprintf("%s?userId=%s", usersPath, url.QueryEscape(prefix))
+	}
+	var users []User
+	if err := c.doSecurity(ctx, "GET", path, nil, &users, nil); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (c *Client) getUser(ctx context.Context, id string) (User, error) {
+	if id == "" {
+		return User{}, newError(ErrInvalidInput, userIDRequiredInfo)
+	}
+	users, err := c.getUsers(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	for _, u := range users {
+		if u.UserID == id {
+			return u, nil
+		}
+	}
+	return User{}, newError(ErrUserNotFound, userNotFoundInfo, id)
+}
+
+func (c *Client) userExists(ctx context.Context, id string) (bool, error) {
+	_, err := c.getUser(ctx, id)
+	if errors.Is(err, ErrUserNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// doSecurity calls a security REST endpoint, see restBackend.doSecurity
+func (c *Client) doSecurity(ctx context.Context, method, path string, payload, out interface{}, notFound error) error {
+	if err := c.requireFeature(ctx, FeatureRESTSecurity); err != nil {
+		return err
+	}
+	return c.doREST(ctx, method, path, payload, out, notFound)
+}
+
+// validateUserRoles checks that the roles granted to a user exist
+func (c *Client) validateUserRoles(ctx context.Context, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	rIDs, err := c.getRoleIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if !entryExists(rIDs, role) {
+			return newError(ErrRoleNotFound, roleNotFoundInfo, role)
+		}
+	}
+	return nil
+}
+
+func validateUser(user User) error {
+	if user.UserID == "" || user.FirstName == "" || user.LastName == "" || user.EmailAddress == "" {
+		return newError(ErrInvalidInput, createUserRequiredInfo)
+	}
+	if err := validateUserEmail(user.EmailAddress); err != nil {
+		return err
+	}
+	return validateUserStatus(user.Status)
+}
+
+// validateUserUpdate validates the fields changed by an update. The profile of the users
+// of an external source eg: LDAP is not managed by nexus and is not validated
+func validateUserUpdate(before, after User) error {
+	if before.Source != UserSourceDefault {
+		return nil
+	}
+	if (after.FirstName != before.FirstName && after.FirstName == "") ||
+		(after.LastName != before.LastName && after.LastName == "") ||
+		(after.EmailAddress != before.EmailAddress && after.EmailAddress == "") {
+		return newError(ErrInvalidInput, updateUserRequiredInfo)
+	}
+	if after.EmailAddress != before.EmailAddress {
+		if err := validateUserEmail(after.EmailAddress); err != nil {
+			return err
+		}
+	}
+	if after.Status != before.Status {
+		return validateUserStatus(after.Status)
+	}
+	return nil
+}
+
+func validateUserEmail(email string) error {
+	if _, err := mail.ParseAddress(email); err != nil {
+		return newError(ErrInvalidInput, emailNotValidInfo, email)
+	}
+	return nil
+}
+
+func validateUserStatus(status string) error {
+	if !entryExists(UserStatuses, strings.ToLower(status)) {
+		return newError(ErrInvalidInput, userStatusNotValidInfo, status, UserStatuses)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package nxrm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateUserUpdate(t *testing.T) {
+	local := User{UserID: "user", FirstName: "first", LastName: "last", EmailAddress: "user@example.com", Source: UserSourceDefault, Status: UserActive}
+	external := User{UserID: "user", Source: "LDAP", Status: UserActive}
+	update := func(user User, f func(*User)) User {
+		f(&user)
+		return user
+	}
+	tests := []struct {
+		name    string
+		before  User
+		after   User
+		wantErr bool
+	}{
+		{"roles of an external user", external, update(external, func(u *User) { u.Roles = []string{"role"} }), false},
+		{"unchanged profile", local, update(local, func(u *User) { u.Roles = []string{"role"} }), false},
+		{"changed name", local, update(local, func(u *User) { u.FirstName = "other" }), false},
+		{"removed name", local, update(local, func(u *User) { u.LastName = "" }), true},
+		{"invalid email", local, update(local, func(u *User) { u.EmailAddress = "invalid" }), true},
+		{"invalid status", local, update(local, func(u *User) { u.Status = "blocked" }), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateUserUpdate(tt.before, tt.after)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("validateUserUpdate() error = %v, want %v", err, ErrInvalidInput)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("validateUserUpdate() error = %v", err)
+			}
+		})
+	}
+}
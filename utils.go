@@ -2,104 +2,140 @@ package nxrm
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"os"
-	"runtime"
 	"strings"
+	"time"
 )
 
-/*
-logError prints error
-@param err error  error details
-@return void
-*/
-func logError(err error, errorMessage string) {
-	if err != nil {
-		log.Println(errorMessage)
-		log.Fatal(err)
-	}
-}
-
-func logJsonMarshalError(err error, funcName string) {
-	if err != nil {
-		log.Println(fmt.Sprintf("%s : %s", funcName, jsonMarshalError))
-		os.Exit(1)
-	}
-}
-
-func logJsonUnmarshalError(err error, funcName string) {
-	if err != nil {
-		log.Println(fmt.Sprintf("%s : %s", funcName, jsonUnmarshalError))
-		os.Exit(1)
-	}
-}
-
 /*
 createBaseRequest create the base request for a HTTP request
+@param ctx      context.Context request context
 @param method   string          http request method eg: GET, POST, etc
 @param url      string          http request url
 @param body     []byte          request body
-@param user     m.AuthUser      User authentication details
-@param verbose  boolean         prints verbose logs if set to true
 @return *http.Request   HTTP base request
+@return error
 */
-func createBaseRequest(method, url string, requestBody RequestBody) *http.Request {
-	if SkipTLSVerification {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
+func (c *Client) createBaseRequest(ctx context.Context, method, url string, requestBody RequestBody) (*http.Request, error) {
 	var (
 		req *http.Request
 		err error
 	)
 	if requestBody.Json != nil {
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(requestBody.Json))
-		logError(err, "Error creating the request")
+		req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(requestBody.Json))
+		if err != nil {
+			return nil, fmt.Errorf("error creating the request : %w", err)
+		}
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Accept", "application/json")
 	} else if requestBody.Text != "" {
-		req, err = http.NewRequest(method, url, strings.NewReader(requestBody.Text))
+		req, err = http.NewRequestWithContext(ctx, method, url, strings.NewReader(requestBody.Text))
+		if err != nil {
+			return nil, fmt.Errorf("error creating the request : %w", err)
+		}
 		req.Header.Set("Content-Type", "text/plain")
-		logError(err, "Error creating the request")
 	} else {
-		req, err = http.NewRequest(method, url, nil)
-		logError(err, "Error creating the request")
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating the request : %w", err)
+		}
 	}
-	req.SetBasicAuth(AuthUser.Username, AuthUser.Password)
-	if Verbose {
-		fmt.Println("Request Url:", req.URL)
-		fmt.Println("Request Headers:", req.Header)
-		fmt.Println("Request Body:", req.Body)
+	req.SetBasicAuth(c.user.Username, c.user.Password)
+	if c.verbose {
+		// the credentials of the client are not logged
+		headers := req.Header.Clone()
+		headers.Set("Authorization", "REDACTED")
+		c.logger.Printf("Request Url: %s", req.URL)
+		c.logger.Printf("Request Headers: %v", headers)
+		if requestBody.Json != nil {
+			c.logger.Printf("Request Body: %s", requestBody.Json)
+		} else {
+			c.logger.Printf("Request Body: %s", requestBody.Text)
+		}
 	}
-	return req
+	return req, nil
 }
 
 /*
-httpRequest makes a request to the remote server via a proxy server
-@param user     m.AuthUser      User authentication details
+httpRequest makes a request to the remote server using the http client of the Client
 @param req      *http.Request   HTTP base request
-@param verbose  boolean         prints verbose logs if set to true
 @return []byte  response body
 @return string  response status
+@return http.Header response headers
+@return error   error making the request or reading the response
 */
-func httpRequest(req *http.Request) ([]byte, string) {
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	logError(err, "There was a problem in making the request")
+func (c *Client) httpRequest(req *http.Request) ([]byte, string, http.Header, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("there was a problem in making the request : %w", err)
+	}
 
 	defer resp.Body.Close()
 	respBody, err := ioutil.ReadAll(resp.Body)
-	logError(err, "There was a problem reading the response body")
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("there was a problem reading the response body : %w", err)
+	}
+
+	if c.verbose {
+		c.logger.Printf("Response Headers: %v", resp.Header)
+		c.logger.Printf("Response Status: %s", resp.Status)
+		c.logger.Printf("Response Body: %s", string(respBody))
+	}
+	return respBody, resp.Status, resp.Header, nil
+}
+
+// doRequest creates and executes a request
+func (c *Client) doRequest(ctx context.Context, method, url string, requestBody RequestBody) ([]byte, string, error) {
+	respBody, status, _, err := c.doRequestWithHeader(ctx, method, url, requestBody)
+	return respBody, status, err
+}
+
+// doRequestWithHeader creates and executes a request and also returns the response headers.
+// Requests which failed with a transient error are retried according to the retry policy of the client
+func (c *Client) doRequestWithHeader(ctx context.Context, method, url string, requestBody RequestBody) ([]byte, string, http.Header, error) {
+	safe := isIdempotent(method) || isRetrySafe(ctx)
+	for attempt := 1; ; attempt++ {
+		respBody, status, header, err := c.doAttempt(ctx, method, url, requestBody)
+		var retry bool
+		if err != nil {
+			retry = ctx.Err() == nil && (safe || isConnectionError(err))
+		} else {
+			retry = safe && c.retryPolicy.retryableStatus(statusCode(status))
+		}
+		if !retry || attempt >= c.retryPolicy.MaxAttempts {
+			return respBody, status, header, err
+		}
+		wait := c.retryPolicy.wait(attempt, header)
+		// the request is not retried when the context expires before the next attempt
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return respBody, status, header, err
+		}
+		if c.debug {
+			c.logger.Printf(requestRetryInfo, method, url, attempt, c.retryPolicy.MaxAttempts, wait)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, "", nil, err
+		}
+	}
+}
 
-	if Verbose {
-		fmt.Println("Response Headers:", resp.Header)
-		fmt.Println("Response Status:", resp.Status)
-		fmt.Println("Response Body:", string(respBody))
+// doAttempt makes a single attempt of a request.
+// The default timeout of the client is applied on top of the deadline of ctx
+func (c *Client) doAttempt(ctx context.Context, method, url string, requestBody RequestBody) ([]byte, string, http.Header, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
 	}
-	return respBody, resp.Status
+	req, err := c.createBaseRequest(ctx, method, url, requestBody)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return c.httpRequest(req)
 }
 
 // fileExists - Checks if a file exists
@@ -113,41 +149,21 @@ func fileExists(fileName string) bool {
 	return true
 }
 
-// createFile - creates a new file
-// @fileName: name or path to the file
-func createFile(fileName string) *os.File {
-	f, err := os.Create(fileName)
-	logError(err, fmt.Sprintf("There was an error while creating the file %s", fileName))
-	return f
-}
-
 // readFile - reads a file and returns the data in string format.
 // The function checks if the file exists or not before reading the file
 // @fileName: name or path to the file
-func readFile(fileName string) string {
-	var (
-		data []byte
-		err  error
-	)
-	if fileExists(fileName) {
-		data, err = ioutil.ReadFile(fileName)
-		logError(err, "There was an error reading the file.")
-	} else {
-		log.Printf("File %q was not found", fileName)
-		os.Exit(1)
+func readFile(fileName string) (string, error) {
+	if !fileExists(fileName) {
+		return "", newError(ErrFileNotFound, "File %q was not found", fileName)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return "", fmt.Errorf("there was an error reading the file %q : %w", fileName, err)
 	}
 	if string(data) == "" {
-		log.Printf("The file %q is empty", fileName)
-		os.Exit(1)
+		return "", newError(ErrInvalidInput, "The file %q is empty", fileName)
 	}
-	return string(data)
-}
-
-// readStringSliceFromFile - reads the data from a file and inputs every new line into a slice and returns the slice
-// @fileName: name or path to the file
-func readStringSliceFromFile(fileName string) []string {
-	data := readFile(fileName)
-	return strings.Split(strings.TrimSpace(data), "\n")
+	return string(data), nil
 }
 
 // writeFile - writes data to a file.
@@ -155,30 +171,11 @@ func readStringSliceFromFile(fileName string) []string {
 // if the file exists then the file will be overwritten with the new data
 // @fileName: name or path to the file
 // @data: the data in []byte format
-func writeFile(fileName string, data []byte) {
-	err := ioutil.WriteFile(fileName, data, 0644)
-	logError(err, "There was an error writing to the file.")
-}
-
-// writeFile - writes a string slice to a file. Every new line will be written in a new line of the file
-// If the file does not exists, a new file will be created
-// if the file exists then the file will be overwritten with the new data
-// @fileName: name or path to the file
-// @slice: a string slice
-func writeStringSliceToFile(fileName string, slice []string) {
-	f := createFile(fileName)
-	sep := "\n"
-	for _, line := range slice {
-		if _, err := f.WriteString(line + sep); err != nil {
-			panic(err)
-		}
-	}
-}
-
-func printStringSlice(slice []string) {
-	for _, s := range slice {
-		fmt.Println(s)
+func writeFile(fileName string, data []byte) error {
+	if err := ioutil.WriteFile(fileName, data, 0644); err != nil {
+		return fmt.Errorf("there was an error writing to the file %q : %w", fileName, err)
 	}
+	return nil
 }
 
 func entryExists(slice []string, entry string) bool {
@@ -190,11 +187,6 @@ func entryExists(slice []string, entry string) bool {
 	return false
 }
 
-func getfuncName() string {
-	pc, _, _, _ := runtime.Caller(1)
-	return runtime.FuncForPC(pc).Name()
-}
-
 func toLower(s string) string {
 	return strings.ToLower(s)
 }
@@ -208,11 +200,12 @@ func getSliceIndex(slice []string, entry string) int {
 	return -1
 }
 
+// removeEntryFromSlice - removes an entry from a slice.
+// The slice is returned unchanged if the entry does not exist
 func removeEntryFromSlice(slice []string, entry string) []string {
 	i := getSliceIndex(slice, entry)
 	if i == -1 {
-		log.Printf("The entry %s does not exist, hence it cannot be removed", entry)
-		os.Exit(1)
+		return slice
 	}
 	return append(slice[:i], slice[i+1:]...)
 }